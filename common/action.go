@@ -0,0 +1,227 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	Actions    map[string][]Action = make(map[string][]Action) // Parsed action strings
+	Parameters map[string]string   = map[string]string{
+		"proportion_set": "float",
+		"master_count":   "int",
+		"slave_count":    "int",
+		"desktop_switch": "int",
+	} // Argument types of parameterized actions
+	Globals []string = []string{
+		"hints",
+		"session_restore",
+		"desktop_switch",
+		"scratchpad_toggle",
+		"profile_save",
+		"profile_load",
+		"mode_",
+		"restart",
+		"exit",
+	} // Action prefixes executed once instead of per workspace
+)
+
+type Action struct {
+	Name    string   // Action name
+	Args    []string // Action arguments
+	Command string   // Action text as written
+}
+
+func ParseActions(text string) ([]Action, error) {
+	if actions, ok := Actions[text]; ok {
+		return actions, nil
+	}
+
+	// Split action sequence on semicolons outside of quotes
+	actions := []Action{}
+	for _, part := range splitQuoted(text, func(r rune) bool { return r == ';' }, false) {
+		fields := SplitFields(part)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// Validate action arguments
+		action := Action{Name: fields[0], Args: fields[1:], Command: strings.TrimSpace(part)}
+		if err := action.validate(); err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
+
+func SplitFields(text string) []string {
+	fields := []string{}
+
+	// Split on whitespace outside of quotes and remove quotes
+	for _, field := range splitQuoted(text, unicode.IsSpace, true) {
+		if len(field) > 0 {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+func CornerAction(name string, desktop uint, screen uint) string {
+
+	// Prefer desktop over screen over global corners
+	if action, ok := Config.CornersDesktop[strconv.Itoa(int(desktop))][name]; ok {
+		return action
+	}
+	if action, ok := Config.CornersScreen[strconv.Itoa(int(screen))][name]; ok {
+		return action
+	}
+
+	return Config.Corners[name]
+}
+
+func (a Action) String() string {
+	return strings.Join(append([]string{a.Name}, a.Args...), " ")
+}
+
+func (a Action) IsGlobal() bool {
+	for _, prefix := range Globals {
+		if strings.HasPrefix(a.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a Action) Int() int {
+	value, _ := strconv.Atoi(a.Args[0])
+	return value
+}
+
+func (a Action) Float() float64 {
+	value, _ := strconv.ParseFloat(a.Args[0], 64)
+	return value
+}
+
+func splitQuoted(text string, separator func(rune) bool, strip bool) []string {
+	parts := []string{}
+	part := strings.Builder{}
+
+	// Split text on separators that are not enclosed by quotes
+	quote := rune(0)
+	for _, r := range text {
+		switch {
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+			if strip {
+				continue
+			}
+		case quote != 0 && r == quote:
+			quote = 0
+			if strip {
+				continue
+			}
+		case quote == 0 && separator(r):
+			parts = append(parts, part.String())
+			part.Reset()
+			continue
+		}
+		part.WriteRune(r)
+	}
+
+	return append(parts, part.String())
+}
+
+func (a Action) validate() error {
+	typ, ok := Parameters[a.Name]
+	if !ok {
+		return nil
+	}
+
+	// Check number of arguments
+	if len(a.Args) != 1 {
+		return fmt.Errorf("%s expects 1 argument, got %d", a.Name, len(a.Args))
+	}
+
+	// Check type of arguments
+	switch typ {
+	case "int":
+		value, err := strconv.Atoi(a.Args[0])
+		if err != nil || value < 0 {
+			return fmt.Errorf("%s expects a positive integer, got %s", a.Name, a.Args[0])
+		}
+	case "float":
+		value, err := strconv.ParseFloat(a.Args[0], 64)
+		if err != nil || value <= 0.0 || value >= 1.0 {
+			return fmt.Errorf("%s expects a number between 0.0 and 1.0, got %s", a.Name, a.Args[0])
+		}
+	}
+
+	return nil
+}
+
+func parseActions() {
+	actions := make(map[string][]Action)
+
+	// Collect action strings of bindings
+	sources := map[string][]string{}
+	for action := range Config.Keys {
+		if !strings.HasPrefix(action, "mod_") {
+			sources["keys"] = append(sources["keys"], action)
+		}
+	}
+	for action := range Config.Mouse {
+		if !IsInList(action, []string{"window_move", "window_resize"}) {
+			sources["mouse"] = append(sources["mouse"], action)
+		}
+	}
+	for _, action := range Config.Corners {
+		sources["corners"] = append(sources["corners"], action)
+	}
+	for index, corners := range Config.CornersScreen {
+		for _, action := range corners {
+			sources["corners_screen."+index] = append(sources["corners_screen."+index], action)
+		}
+	}
+	for index, corners := range Config.CornersDesktop {
+		for _, action := range corners {
+			sources["corners_desktop."+index] = append(sources["corners_desktop."+index], action)
+		}
+	}
+	for _, action := range Config.Systray {
+		sources["systray"] = append(sources["systray"], action)
+	}
+	for _, entry := range Config.TilingIcon {
+		if len(entry) > 0 {
+			sources["tiling_icon"] = append(sources["tiling_icon"], entry[0])
+		}
+	}
+	for name, keys := range Config.Modes {
+		for action := range keys {
+			sources["modes."+name] = append(sources["modes."+name], action)
+		}
+	}
+
+	// Parse action strings once
+	for section, texts := range sources {
+		for _, text := range texts {
+			if _, ok := actions[text]; ok || len(text) == 0 {
+				continue
+			}
+			parsed, err := ParseActions(text)
+			if err != nil {
+				log.Warn("Error parsing action \"", text, "\" in [", section, "]: ", err)
+				continue
+			}
+			actions[text] = parsed
+		}
+	}
+
+	Actions = actions
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"path/filepath"
 )
@@ -27,9 +28,22 @@ type Arguments struct {
 		Property string   // Argument for dbus property name
 		P        []string // Argument for dbus positional values
 	}
+	Ipc struct {
+		Listen   bool     // Argument for ipc listen flag
+		Method   string   // Argument for ipc method name
+		Property string   // Argument for ipc property name
+		P        []string // Argument for ipc positional values
+	}
+	Tree struct {
+		Enabled bool   // Argument for tree subcommand
+		Json    bool   // Argument for tree json output
+		Desktop int    // Argument for tree desktop filter
+		Screen  int    // Argument for tree screen filter
+		Class   string // Argument for tree class filter
+	}
 }
 
-func InitArgs(introspect map[string][]string) {
+func InitArgs(introspect func() map[string][]string, ipcIntrospect func() map[string][]string) {
 
 	// Command line arguments
 	flag.StringVar(&Args.Cache, "cache", filepath.Join(CacheFolderPath(Build.Name), Build.Version), "cache folder path")
@@ -58,31 +72,27 @@ func InitArgs(introspect map[string][]string) {
 	dbus.StringVar(&Args.Dbus.Property, "property", "", "dbus property reader")
 	Args.Dbus.P = []string{}
 
+	// Subcommand line arguments
+	ipc := flag.NewFlagSet("ipc", flag.ExitOnError)
+	ipc.BoolVar(&Args.Ipc.Listen, "listen", false, "ipc listen mode")
+	ipc.StringVar(&Args.Ipc.Method, "method", "", "ipc method caller")
+	ipc.StringVar(&Args.Ipc.Property, "property", "", "ipc property reader")
+	Args.Ipc.P = []string{}
+
+	// Subcommand line arguments
+	tree := flag.NewFlagSet("tree", flag.ExitOnError)
+	tree.BoolVar(&Args.Tree.Json, "json", false, "tree json output")
+	tree.IntVar(&Args.Tree.Desktop, "desktop", -1, "tree desktop filter")
+	tree.IntVar(&Args.Tree.Screen, "screen", -1, "tree screen filter")
+	tree.StringVar(&Args.Tree.Class, "class", "", "tree class filter")
+
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
 		case "dbus":
 
 			// Subcommand line usage text
 			dbus.Usage = func() {
-				fmt.Fprintf(dbus.Output(), "%s\n\nUsage:\n", Build.Summary)
-				dbus.PrintDefaults()
-
-				if len(introspect) > 0 {
-					if methods, ok := introspect["Methods"]; ok {
-						fmt.Fprintf(dbus.Output(), "\nMethods:\n")
-						for _, method := range methods {
-							fmt.Fprintf(dbus.Output(), "  %s dbus -method %s\n", Build.Name, method)
-						}
-					}
-					if properties, ok := introspect["Properties"]; ok {
-						fmt.Fprintf(dbus.Output(), "\nProperties:\n")
-						for _, property := range properties {
-							fmt.Fprintf(dbus.Output(), "  %s dbus -property %s\n", Build.Name, property)
-						}
-					}
-				} else {
-					fmt.Fprintf(dbus.Output(), "\n>>> start %s to see further information's <<<\n", Build.Name)
-				}
+				FlagUsage(dbus, introspect())
 			}
 
 			// Parse subcommand line arguments
@@ -94,10 +104,69 @@ func InitArgs(introspect map[string][]string) {
 				dbus.Usage()
 				os.Exit(2)
 			}
+		case "ipc":
+
+			// Subcommand line usage text
+			ipc.Usage = func() {
+				FlagUsage(ipc, ipcIntrospect())
+			}
+
+			// Parse subcommand line arguments
+			FlagParse(ipc, os.Args[2:])
+			Args.Ipc.P = ipc.Args()
+
+			// Check subcommand line arguments
+			if !Args.Ipc.Listen && len(Args.Ipc.Method) == 0 && len(Args.Ipc.Property) == 0 {
+				ipc.Usage()
+				os.Exit(2)
+			}
+		case "tree":
+
+			// Subcommand line usage text
+			tree.Usage = func() {
+				fmt.Fprintf(tree.Output(), "%s\n\nUsage:\n", Build.Summary)
+				tree.PrintDefaults()
+			}
+
+			// Parse subcommand line arguments
+			FlagParse(tree, os.Args[2:])
+			Args.Tree.Enabled = true
+		}
+	}
+}
+
+func FlagUsage(flags *flag.FlagSet, introspect map[string][]string) {
+	fmt.Fprintf(flags.Output(), "%s\n\nUsage:\n", Build.Summary)
+	flags.PrintDefaults()
+
+	if len(introspect) > 0 {
+		if methods, ok := introspect["Methods"]; ok {
+			fmt.Fprintf(flags.Output(), "\nMethods:\n")
+			for _, method := range methods {
+				fmt.Fprintf(flags.Output(), "  %s %s -method %s\n", Build.Name, flags.Name(), method)
+			}
+		}
+		if properties, ok := introspect["Properties"]; ok {
+			fmt.Fprintf(flags.Output(), "\nProperties:\n")
+			for _, property := range properties {
+				fmt.Fprintf(flags.Output(), "  %s %s -property %s\n", Build.Name, flags.Name(), property)
+			}
 		}
+		if signals, ok := introspect["Signals"]; ok {
+			fmt.Fprintf(flags.Output(), "\nSignals:\n")
+			for _, signal := range signals {
+				fmt.Fprintf(flags.Output(), "  %s %s -listen Signal:%s\n", Build.Name, flags.Name(), signal)
+			}
+		}
+	} else {
+		fmt.Fprintf(flags.Output(), "\n>>> start %s to see further information's <<<\n", Build.Name)
 	}
 }
 
+func SocketFilePath() string {
+	return strings.TrimSuffix(Args.Lock, filepath.Ext(Args.Lock)) + ".sock"
+}
+
 func FlagParse(flags *flag.FlagSet, args []string) {
 	pargs := []string{}
 
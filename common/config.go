@@ -3,6 +3,8 @@ package common
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"strings"
 
 	"encoding/json"
 	"path/filepath"
@@ -19,27 +21,76 @@ var (
 )
 
 type Configuration struct {
-	TilingEnabled     bool              `toml:"tiling_enabled"`      // Tile windows on startup
-	TilingLayout      string            `toml:"tiling_layout"`       // Initial tiling layout
-	TilingCycle       []string          `toml:"tiling_cycle"`        // Cycle layout order
-	TilingGui         int               `toml:"tiling_gui"`          // Time duration of gui
-	TilingIcon        [][]string        `toml:"tiling_icon"`         // Menu entries of systray
-	WindowIgnore      [][]string        `toml:"window_ignore"`       // Regex to ignore windows
-	WindowMastersMax  int               `toml:"window_masters_max"`  // Maximum number of allowed masters
-	WindowSlavesMax   int               `toml:"window_slaves_max"`   // Maximum number of allowed slaves
-	WindowGapSize     int               `toml:"window_gap_size"`     // Gap size between windows
-	WindowFocusDelay  int               `toml:"window_focus_delay"`  // Window focus delay when hovered
-	WindowDecoration  bool              `toml:"window_decoration"`   // Show window decorations
-	ProportionStep    float64           `toml:"proportion_step"`     // Master-slave area step size proportion
-	ProportionMin     float64           `toml:"proportion_min"`      // Window size minimum proportion
-	EdgeMargin        []int             `toml:"edge_margin"`         // Margin values of tiling area
-	EdgeMarginPrimary []int             `toml:"edge_margin_primary"` // Margin values of primary tiling area
-	EdgeCornerSize    int               `toml:"edge_corner_size"`    // Size of square defining edge corners
-	EdgeCenterSize    int               `toml:"edge_center_size"`    // Length of rectangle defining edge centers
-	Colors            map[string][]int  `toml:"colors"`              // List of color values for gui elements
-	Keys              map[string]string `toml:"keys"`                // Event bindings for keyboard shortcuts
-	Corners           map[string]string `toml:"corners"`             // Event bindings for hot-corner actions
-	Systray           map[string]string `toml:"systray"`             // Event bindings for systray icon
+	TilingEnabled     bool                         `toml:"tiling_enabled"`      // Tile windows on startup
+	TilingLayout      string                       `toml:"tiling_layout"`       // Initial tiling layout
+	TilingCycle       []string                     `toml:"tiling_cycle"`        // Cycle layout order
+	TilingGui         int                          `toml:"tiling_gui"`          // Time duration of gui
+	TilingBar         int                          `toml:"tiling_bar"`          // Height of persistent layout bar
+	TilingTabs        int                          `toml:"tiling_tabs"`         // Height of tabbed layout tab bar
+	TilingIcon        [][]string                   `toml:"tiling_icon"`         // Menu entries of systray
+	WindowIgnore      [][]string                   `toml:"window_ignore"`       // Regex to ignore windows
+	WindowMastersMax  int                          `toml:"window_masters_max"`  // Maximum number of allowed masters
+	WindowSlavesMax   int                          `toml:"window_slaves_max"`   // Maximum number of allowed slaves
+	WindowGapSize     int                          `toml:"window_gap_size"`     // Gap size between windows
+	WindowFocusDelay  int                          `toml:"window_focus_delay"`  // Window focus delay when hovered
+	WindowDecoration  bool                         `toml:"window_decoration"`   // Show window decorations
+	WindowSession     bool                         `toml:"window_session"`      // Record clients for session restore
+	ProportionStep    float64                      `toml:"proportion_step"`     // Master-slave area step size proportion
+	ProportionMin     float64                      `toml:"proportion_min"`      // Window size minimum proportion
+	EdgeMargin        []int                        `toml:"edge_margin"`         // Margin values of tiling area
+	EdgeMarginPrimary []int                        `toml:"edge_margin_primary"` // Margin values of primary tiling area
+	EdgeCornerSize    int                          `toml:"edge_corner_size"`    // Size of square defining edge corners
+	EdgeCenterSize    int                          `toml:"edge_center_size"`    // Length of rectangle defining edge centers
+	EdgeSnap          bool                         `toml:"edge_snap"`           // Snap dragged windows to screen edges
+	EdgeCornerDelay   int                          `toml:"edge_corner_delay"`   // Dwell time before hot-corners fire
+	EdgeCornerPush    int                          `toml:"edge_corner_push"`    // Push time against the edge before hot-corners fire
+	EdgeCornerRepeat  int                          `toml:"edge_corner_repeat"`  // Repeat interval of held hot-corners
+	Colors            map[string][]int             `toml:"colors"`              // List of color values for gui elements
+	Keys              map[string]string            `toml:"keys"`                // Event bindings for keyboard shortcuts
+	Mouse             map[string]string            `toml:"mouse"`               // Event bindings for mouse buttons
+	Corners           map[string]string            `toml:"corners"`             // Event bindings for hot-corner actions
+	CornersScreen     map[string]map[string]string `toml:"corners_screen"`      // Event bindings for hot-corners per screen
+	CornersDesktop    map[string]map[string]string `toml:"corners_desktop"`     // Event bindings for hot-corners per desktop
+	Systray           map[string]string            `toml:"systray"`             // Event bindings for systray icon
+	Modes             map[string]map[string]string `toml:"modes"`               // Event bindings for keyboard modes
+	Gui               Gui                          `toml:"gui"`                 // Appearance of overlay window
+	Layouts           []LayoutDefinition           `toml:"layouts"`             // User defined declarative layouts
+	Rules             []Rule                       `toml:"rules"`               // Window rules for placement and tiling
+}
+
+type Gui struct {
+	Position string   `toml:"position"`  // Anchor position of overlay
+	Scale    float64  `toml:"scale"`     // Scale factor of desktop preview
+	Font     string   `toml:"font"`      // Path to TTF font file
+	FontSize int      `toml:"font_size"` // Size of text font
+	Radius   int      `toml:"radius"`    // Corner radius of client rectangles
+	Opacity  float64  `toml:"opacity"`   // Opacity of overlay window
+	Content  []string `toml:"content"`   // Content drawn in client rectangles
+}
+
+type LayoutDefinition struct {
+	Name string     `toml:"name"` // Declarative layout name
+	Root LayoutNode `toml:"root"` // Declarative layout root node
+}
+
+type LayoutNode struct {
+	Direction string       `toml:"direction"` // Split direction of node or stacking direction of slot
+	Ratio     float64      `toml:"ratio"`     // Proportion of first child node
+	Slot      string       `toml:"slot"`      // Slot for masters or slaves
+	Children  []LayoutNode `toml:"children"`  // Child nodes of split
+}
+
+type Rule struct {
+	Class   string `toml:"class"`   // Regex matching window class
+	Name    string `toml:"name"`    // Regex matching window name
+	Type    string `toml:"type"`    // Regex matching window type
+	Float   bool   `toml:"float"`   // Exclude window from tiling
+	Role    string `toml:"role"`    // Start window as master or slave
+	Desktop *uint  `toml:"desktop"` // Move window to desktop
+	Screen  *uint  `toml:"screen"`  // Move window to screen
+	Layout  string `toml:"layout"`  // Switch workspace to layout
+
+	patterns []*regexp.Regexp // Compiled class, name and type patterns
 }
 
 func InitConfig() {
@@ -95,6 +146,12 @@ func readConfig(configFilePath string, initial bool) {
 		}
 	}
 
+	// Parse action strings
+	parseActions()
+
+	// Parse window rules
+	parseRules()
+
 	// Print shortcut infos
 	if initial {
 		keys, _ := json.MarshalIndent(Config.Keys, "", "  ")
@@ -107,6 +164,48 @@ func readConfig(configFilePath string, initial bool) {
 	}
 }
 
+func parseRules() {
+	for i := range Config.Rules {
+		rule := &Config.Rules[i]
+		rule.patterns = nil
+
+		// Compile rule patterns
+		for _, pattern := range []string{rule.Class, rule.Name, rule.Type} {
+			reg, err := regexp.Compile(strings.ToLower(pattern))
+			if err != nil {
+				log.Warn("Error parsing window rule ", pattern, ": ", err)
+				rule.patterns = nil
+				break
+			}
+			rule.patterns = append(rule.patterns, reg)
+		}
+	}
+}
+
+func (r *Rule) Match(class string, name string, types []string) bool {
+	if len(r.patterns) != 3 {
+		return false
+	}
+
+	// Match window class, name and types
+	return matchPattern(r.patterns[0], []string{class}) && matchPattern(r.patterns[1], []string{name}) && matchPattern(r.patterns[2], types)
+}
+
+func matchPattern(reg *regexp.Regexp, values []string) bool {
+	if len(reg.String()) == 0 {
+		return true
+	}
+
+	// Match any of the values
+	for _, value := range values {
+		if reg.MatchString(strings.ToLower(value)) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func watchConfig(configFilePath string) {
 
 	// Init file watcher
@@ -96,6 +96,30 @@ func IsInsideRect(p Point, g Geometry) bool {
 	return p.X >= x && p.X <= (x+w) && p.Y >= y && p.Y <= (y+h)
 }
 
+func IsInDirection(from Geometry, to Geometry, direction string) (int, bool) {
+	fc, tc := from.Center(), to.Center()
+	dx, dy := tc.X-fc.X, tc.Y-fc.Y
+
+	// Obtain distances along and across direction
+	primary, secondary := 0, 0
+	switch direction {
+	case "left":
+		primary, secondary = -dx, dy
+	case "right":
+		primary, secondary = dx, dy
+	case "up":
+		primary, secondary = -dy, dx
+	case "down":
+		primary, secondary = dy, dx
+	}
+	if primary <= 0 {
+		return 0, false
+	}
+
+	// Penalize offsets across direction
+	return primary + 2*int(math.Abs(float64(secondary))), true
+}
+
 func IsInList(item string, items []string) bool {
 	for i := 0; i < len(items); i++ {
 		if items[i] == item {
@@ -1,6 +1,7 @@
 package desktop
 
 import (
+	"github.com/leukipp/cortile/v2/common"
 	"github.com/leukipp/cortile/v2/store"
 )
 
@@ -20,7 +21,9 @@ type Layout interface {
 	DecreaseSlave()
 	IncreaseProportion()
 	DecreaseProportion()
-	UpdateProportions(c *store.Client, d *store.Directions)
+	SetProportion(p float64)
+	UpdateProportions(c *store.Client, g *common.Geometry, d *store.Directions)
+	GetProportions() map[string][]float64
 	GetManager() *store.Manager
 	GetName() string
 }
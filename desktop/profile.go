@@ -0,0 +1,257 @@
+package desktop
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type Profile struct {
+	Name       string              // Profile name
+	Workspaces []*ProfileWorkspace // List of workspace settings
+}
+
+type ProfileWorkspace struct {
+	Location store.Location   // Desktop and screen location
+	Layout   string           // Active layout name
+	Tiling   bool             // Tiling is enabled
+	Layouts  []*ProfileLayout // List of layout settings
+}
+
+type ProfileLayout struct {
+	Name        string             // Layout name
+	Masters     ProfileClients     // Master slot settings
+	Slaves      ProfileClients     // Slave slot settings
+	Proportions *store.Proportions // Layout proportions
+}
+
+type ProfileClients struct {
+	Maximum int      // Maximum allowed clients
+	Classes []string // Client classes in stacked order
+}
+
+func CreateProfile(name string, workspaces map[store.Location]*Workspace) *Profile {
+	p := &Profile{
+		Name:       name,
+		Workspaces: []*ProfileWorkspace{},
+	}
+
+	// Capture workspace settings
+	for _, ws := range workspaces {
+		pws := &ProfileWorkspace{
+			Location: ws.Location,
+			Layout:   ws.ActiveLayout().GetName(),
+			Tiling:   ws.Tiling,
+			Layouts:  []*ProfileLayout{},
+		}
+
+		// Capture layout settings and client slots
+		for _, l := range ws.Layouts {
+			mg := l.GetManager()
+			pws.Layouts = append(pws.Layouts, &ProfileLayout{
+				Name:        l.GetName(),
+				Masters:     ProfileClients{Maximum: mg.Masters.Maximum, Classes: classes(mg.Masters.Stacked)},
+				Slaves:      ProfileClients{Maximum: mg.Slaves.Maximum, Classes: classes(mg.Slaves.Stacked)},
+				Proportions: mg.Proportions,
+			})
+		}
+		p.Workspaces = append(p.Workspaces, pws)
+	}
+
+	// Sort workspaces by location
+	sort.Slice(p.Workspaces, func(i, j int) bool {
+		li, lj := p.Workspaces[i].Location, p.Workspaces[j].Location
+		return li.Desktop < lj.Desktop || (li.Desktop == lj.Desktop && li.Screen < lj.Screen)
+	})
+
+	return p
+}
+
+func ReadProfile(name string) *Profile {
+	path, ok := profileFilePath(name)
+	if !ok {
+		log.Warn("Invalid profile name ", name)
+		return nil
+	}
+
+	// Read profile file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Warn("Error reading profile ", name, ": ", err)
+		return nil
+	}
+
+	// Parse profile file
+	p := &Profile{}
+	err = json.Unmarshal(data, p)
+	if err != nil {
+		log.Warn("Error parsing profile ", name, ": ", err)
+		return nil
+	}
+
+	log.Info("Read profile ", name, " [", path, "]")
+
+	return p
+}
+
+func ProfileNames() []string {
+	names := []string{}
+
+	// Read profile folder
+	entries, err := os.ReadDir(profileFolderPath())
+	if err != nil {
+		return names
+	}
+
+	// Obtain profile names from files
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+
+	return names
+}
+
+func (p *Profile) Write() bool {
+	path, ok := profileFilePath(p.Name)
+	if !ok {
+		log.Warn("Invalid profile name ", p.Name)
+		return false
+	}
+
+	// Create profile folder if not exists
+	if _, err := os.Stat(profileFolderPath()); os.IsNotExist(err) {
+		os.MkdirAll(profileFolderPath(), 0755)
+	}
+
+	// Parse profile
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		log.Warn("Error parsing profile ", p.Name, ": ", err)
+		return false
+	}
+
+	// Write profile file
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		log.Warn("Error writing profile ", p.Name, ": ", err)
+		return false
+	}
+
+	log.Info("Write profile ", p.Name, " [", path, "]")
+
+	return true
+}
+
+func (p *Profile) Apply(workspaces map[store.Location]*Workspace) {
+	for _, pws := range p.Workspaces {
+		ws, ok := workspaces[pws.Location]
+		if !ok {
+			log.Warn("Invalid profile workspace [workspace-", pws.Location.Desktop, "-", pws.Location.Screen, "]")
+			continue
+		}
+
+		// Apply layout settings and client slots
+		for _, l := range ws.Layouts {
+			for _, pl := range pws.Layouts {
+				if l.GetName() != pl.Name {
+					continue
+				}
+				mg := l.GetManager()
+
+				// Overwrite number of masters and slaves
+				mg.Masters.Maximum = common.MaxInt(common.MinInt(pl.Masters.Maximum, common.Config.WindowMastersMax), 0)
+				mg.Slaves.Maximum = common.MaxInt(common.MinInt(pl.Slaves.Maximum, common.Config.WindowSlavesMax), 0)
+
+				// Overwrite proportions with compatible values
+				if pl.Proportions != nil {
+					mergeProportions(mg.Proportions.MasterSlave, pl.Proportions.MasterSlave)
+					mergeProportions(mg.Proportions.MasterMaster, pl.Proportions.MasterMaster)
+					mergeProportions(mg.Proportions.SlaveSlave, pl.Proportions.SlaveSlave)
+					mergeProportions(mg.Proportions.Splits, pl.Proportions.Splits)
+					mergeProportions(mg.Proportions.Columns, pl.Proportions.Columns)
+					mergeProportions(mg.Proportions.Rows, pl.Proportions.Rows)
+				}
+
+				// Sort clients into saved slots by class
+				ordered := sortByClass(mg.Clients(store.Stacked), append(pl.Masters.Classes, pl.Slaves.Classes...))
+				n := common.MinInt(len(ordered), mg.Masters.Maximum)
+				mg.Masters.Stacked = append([]*store.Client{}, ordered[:n]...)
+				mg.Slaves.Stacked = append([]*store.Client{}, ordered[n:]...)
+			}
+		}
+
+		// Overwrite active layout and tiling state
+		if !ws.SetLayoutByName(pws.Layout) {
+			log.Warn("Invalid profile layout ", pws.Layout, " [", ws.Name, "]")
+		}
+		ws.Tiling = pws.Tiling
+	}
+}
+
+func profileFolderPath() string {
+	return filepath.Join(filepath.Dir(common.Args.Config), "profiles")
+}
+
+func profileFilePath(name string) (string, bool) {
+	if len(name) == 0 || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", false
+	}
+	return filepath.Join(profileFolderPath(), name+".json"), true
+}
+
+func classes(clients []*store.Client) []string {
+	names := []string{}
+	for _, c := range clients {
+		if c == nil {
+			continue
+		}
+		names = append(names, c.Latest.Class)
+	}
+	return names
+}
+
+func sortByClass(clients []*store.Client, classes []string) []*store.Client {
+	ordered := []*store.Client{}
+	used := make(map[*store.Client]bool)
+
+	// Pick first unused client for each saved class
+	for _, class := range classes {
+		for _, c := range clients {
+			if c == nil || used[c] || c.Latest.Class != class {
+				continue
+			}
+			ordered = append(ordered, c)
+			used[c] = true
+			break
+		}
+	}
+
+	// Append remaining clients in current order
+	for _, c := range clients {
+		if c == nil || used[c] {
+			continue
+		}
+		ordered = append(ordered, c)
+	}
+
+	return ordered
+}
+
+func mergeProportions(dst map[int][]float64, src map[int][]float64) {
+	for i, ps := range src {
+		if len(dst[i]) == len(ps) {
+			dst[i] = ps
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package desktop
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	writer *time.Timer // Timer to delay session cache writes
+)
+
+type Session struct {
+	Entries  []*SessionEntry        // List of recorded clients
+	Pending  []*SessionEntry        `json:"-"` // List of clients waiting for placement
+	Existing map[xproto.Window]bool `json:"-"` // Windows existing before restore
+}
+
+type SessionEntry struct {
+	Class    string         // Client window application name
+	Command  []string       // Client process command line
+	Location store.Location // Client desktop and screen location
+	Master   bool           // Client is placed in master area
+	Index    int            // Client index within area
+	Launched bool           `json:"-"` // Client process was relaunched
+}
+
+func CreateSession(tr *Tracker) *Session {
+	s := &Session{
+		Entries: []*SessionEntry{},
+	}
+
+	// Record tracked clients
+	for _, ws := range tr.Workspaces {
+		mg := ws.ActiveLayout().GetManager()
+		for i, c := range mg.Masters.Stacked {
+			if c == nil {
+				continue
+			}
+			s.Entries = append(s.Entries, createSessionEntry(c, ws.Location, true, i))
+		}
+		for i, c := range mg.Slaves.Stacked {
+			if c == nil {
+				continue
+			}
+			s.Entries = append(s.Entries, createSessionEntry(c, ws.Location, false, i))
+		}
+	}
+
+	// Keep clients that are not restored yet
+	if tr.Session != nil {
+		s.Entries = append(s.Entries, tr.Session.Pending...)
+	}
+
+	return s
+}
+
+func ReadSession() *Session {
+	s := &Session{
+		Entries: []*SessionEntry{},
+		Pending: []*SessionEntry{},
+	}
+	if common.CacheDisabled() {
+		return s
+	}
+
+	// Read session cache
+	data, err := os.ReadFile(sessionFilePath())
+	if os.IsNotExist(err) {
+		log.Info("No session cache found")
+		return s
+	}
+
+	// Parse session cache
+	err = json.Unmarshal(data, s)
+	if err != nil {
+		log.Warn("Error reading session cache: ", err)
+		return s
+	}
+	s.Pending = s.Entries
+
+	log.Debug("Read session cache data [", len(s.Entries), " clients]")
+
+	return s
+}
+
+func (s *Session) Write(delay time.Duration) {
+	if common.CacheDisabled() {
+		return
+	}
+
+	// Replace pending session write
+	if writer != nil {
+		writer.Stop()
+	}
+	if delay > 0 {
+		writer = time.AfterFunc(delay, func() { s.Write(0) })
+		return
+	}
+
+	// Parse session cache
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		log.Warn("Error parsing session cache: ", err)
+		return
+	}
+
+	// Write session cache
+	err = os.WriteFile(sessionFilePath(), data, 0644)
+	if err != nil {
+		log.Warn("Error writing session cache: ", err)
+		return
+	}
+
+	log.Trace("Write session cache data [", len(s.Entries), " clients]")
+}
+
+func (s *Session) Restore() bool {
+	if !common.HasFlag("enable-external-commands") {
+		log.Warn("Relaunching session clients disabled")
+		return false
+	}
+
+	// Ignore windows existing before restore
+	if s.Existing == nil {
+		s.Existing = make(map[xproto.Window]bool)
+	}
+	for _, w := range store.Windows.Stacked {
+		s.Existing[w.Id] = true
+	}
+
+	// Relaunch missing clients
+	launched := 0
+	for _, e := range s.Pending {
+		if e.Launched || len(e.Command) == 0 {
+			continue
+		}
+		e.Launched = true
+
+		log.Info("Relaunch session client \"", e.Command[0], "\" [", e.Class, "]")
+
+		// Start detached process
+		cmd := exec.Command(e.Command[0], e.Command[1:]...)
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+		if err := cmd.Start(); err != nil {
+			log.Warn("Error relaunching session client ", e.Class, ": ", err)
+			continue
+		}
+		go cmd.Wait()
+
+		launched += 1
+	}
+
+	log.Info("Relaunched ", launched, " session clients")
+
+	return true
+}
+
+func (s *Session) Match(c *store.Client) *SessionEntry {
+	if s == nil {
+		return nil
+	}
+
+	// Only windows created after restore are placed
+	placed := s.Existing != nil && !s.Existing[c.Window.Id]
+
+	// Obtain first pending entry with client class
+	for i, e := range s.Pending {
+		if e.Launched != placed || e.Class != c.Latest.Class {
+			continue
+		}
+		s.Pending = append(s.Pending[:i:i], s.Pending[i+1:]...)
+
+		// Running clients are not missing anymore
+		if !placed {
+			return nil
+		}
+		return e
+	}
+
+	return nil
+}
+
+func createSessionEntry(c *store.Client, loc store.Location, master bool, index int) *SessionEntry {
+	return &SessionEntry{
+		Class:    c.Latest.Class,
+		Command:  c.Command(),
+		Location: loc,
+		Master:   master,
+		Index:    index,
+	}
+}
+
+func sessionFilePath() string {
+	folder := filepath.Join(common.Args.Cache, "workplaces", store.Workplace.Displays.Name)
+	if _, err := os.Stat(folder); os.IsNotExist(err) {
+		os.MkdirAll(folder, 0755)
+	}
+	return filepath.Join(folder, "session.json")
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/jezek/xgb/xproto"
 
 	"github.com/jezek/xgbutil"
+	"github.com/jezek/xgbutil/mousebind"
 	"github.com/jezek/xgbutil/xevent"
 	"github.com/jezek/xgbutil/xprop"
 
@@ -15,11 +16,16 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+var (
+	trackCallbacksFun []func(*store.Client) // Track events callback functions
+)
+
 type Tracker struct {
 	Clients    map[xproto.Window]*store.Client // List of tracked clients
 	Workspaces map[store.Location]*Workspace   // List of workspaces per location
 	Channels   *Channels                       // Helper for channel communication
 	Handlers   *Handlers                       // Helper for event handlers
+	Session    *Session                        // Recorded session of clients
 
 }
 type Channels struct {
@@ -33,10 +39,11 @@ type Handlers struct {
 	MoveClient   *Handler    // Stores client for tiling after move
 	SwapClient   *Handler    // Stores clients for window swap
 	SwapScreen   *Handler    // Stores client for screen swap
+	SnapClient   *Handler    // Stores client for edge snapping
 }
 
 func (h *Handlers) Active() bool {
-	return h.ResizeClient.Active() || h.MoveClient.Active() || h.SwapClient.Active() || h.SwapScreen.Active()
+	return h.ResizeClient.Active() || h.MoveClient.Active() || h.SwapClient.Active() || h.SwapScreen.Active() || h.SnapClient.Active()
 }
 
 func (h *Handlers) Reset() {
@@ -44,6 +51,7 @@ func (h *Handlers) Reset() {
 	h.MoveClient.Reset()
 	h.SwapClient.Reset()
 	h.SwapScreen.Reset()
+	h.SnapClient.Reset()
 }
 
 type Handler struct {
@@ -73,9 +81,15 @@ func CreateTracker() *Tracker {
 			MoveClient:   &Handler{},
 			SwapClient:   &Handler{},
 			SwapScreen:   &Handler{},
+			SnapClient:   &Handler{},
 		},
 	}
 
+	// Read recorded session
+	if common.Config.WindowSession {
+		tr.Session = ReadSession()
+	}
+
 	// Attach to root events
 	store.OnStateUpdate(tr.onStateUpdate)
 	store.OnPointerUpdate(tr.onPointerUpdate)
@@ -142,6 +156,15 @@ func (tr *Tracker) Write() {
 	tr.Channels.Event <- "windows_change"
 }
 
+func (tr *Tracker) WriteSession(delay time.Duration) {
+	if tr.Session == nil {
+		return
+	}
+
+	// Write session cache of tracked clients
+	CreateSession(tr).Write(delay)
+}
+
 func (tr *Tracker) Tile(ws *Workspace) {
 	if ws.TilingDisabled() {
 		return
@@ -150,6 +173,9 @@ func (tr *Tracker) Tile(ws *Workspace) {
 	// Tile workspace
 	ws.Tile()
 
+	// Write session cache
+	tr.WriteSession(time.Second)
+
 	// Communicate clients change
 	tr.Channels.Event <- "clients_change"
 
@@ -183,6 +209,36 @@ func (tr *Tracker) ClientWorkspace(c *store.Client) *Workspace {
 	return tr.WorkspaceAt(c.Latest.Location.Desktop, c.Latest.Location.Screen)
 }
 
+func (tr *Tracker) SwapScreens(c1 *store.Client, c2 *store.Client) bool {
+	ws1, ws2 := tr.ClientWorkspace(c1), tr.ClientWorkspace(c2)
+	if ws1 == nil || ws2 == nil || ws1 == ws2 || ws1.TilingDisabled() || ws2.TilingDisabled() || c1.IsFloating() || c2.IsFloating() {
+		return false
+	}
+	log.Info("Swap clients across screens [", c1.Latest.Class, "-", c2.Latest.Class, "]")
+
+	// Obtain slots of clients
+	mg1, mg2 := ws1.ActiveLayout().GetManager(), ws2.ActiveLayout().GetManager()
+	master1, index1 := mg1.IsMaster(c1), common.MaxInt(mg1.Index(mg1.Masters, c1), mg1.Index(mg1.Slaves, c1))
+	master2, index2 := mg2.IsMaster(c2), common.MaxInt(mg2.Index(mg2.Masters, c2), mg2.Index(mg2.Slaves, c2))
+
+	// Remove clients from their workspaces
+	ws1.RemoveClient(c1)
+	ws2.RemoveClient(c2)
+
+	// Place clients at the slot of each other
+	c1.Latest.Location, c2.Latest.Location = ws2.Location, ws1.Location
+	ws2.AddClient(c1)
+	mg2.PlaceClient(c1, master2, index2)
+	ws1.AddClient(c2)
+	mg1.PlaceClient(c2, master1, index1)
+
+	// Tile both workspaces
+	tr.Tile(ws1)
+	tr.Tile(ws2)
+
+	return true
+}
+
 func (tr *Tracker) WorkspaceAt(desktop uint, screen uint) *Workspace {
 	location := store.Location{Desktop: desktop, Screen: screen}
 
@@ -213,6 +269,30 @@ func (tr *Tracker) ClientAt(ws *Workspace, p common.Point) *store.Client {
 	return nil
 }
 
+func (tr *Tracker) ClientInDirection(c *store.Client, ws *Workspace, direction string) *store.Client {
+	if c == nil || ws == nil {
+		return nil
+	}
+
+	// Obtain nearest visible client in direction
+	var target *store.Client
+	best := 0
+	for _, co := range ws.VisibleClients() {
+		if co == nil || co == c {
+			continue
+		}
+		distance, ok := common.IsInDirection(c.Latest.Dimensions.Geometry, co.Latest.Dimensions.Geometry, direction)
+		if !ok {
+			continue
+		}
+		if target == nil || distance < best {
+			target, best = co, distance
+		}
+	}
+
+	return target
+}
+
 func (tr *Tracker) ActiveClient() *store.Client {
 	c, exists := tr.Clients[store.Windows.Active.Id]
 
@@ -224,6 +304,15 @@ func (tr *Tracker) ActiveClient() *store.Client {
 	return c
 }
 
+func (tr *Tracker) ScratchpadClient(name string) *store.Client {
+	for _, c := range tr.Clients {
+		if c.Scratchpad == name {
+			return c
+		}
+	}
+	return nil
+}
+
 func (tr *Tracker) unlockClients() {
 	ws := tr.ActiveWorkspace()
 	if ws == nil {
@@ -247,6 +336,9 @@ func (tr *Tracker) trackWindow(w xproto.Window) bool {
 
 	// Client and workspace
 	c := store.CreateClient(w)
+	e := tr.Session.Match(c)
+	tr.handleSessionPlacement(c, e)
+	tr.handleRulePlacement(c)
 	ws := tr.ClientWorkspace(c)
 	if ws == nil {
 		return false
@@ -255,9 +347,12 @@ func (tr *Tracker) trackWindow(w xproto.Window) bool {
 	// Add new client
 	tr.Clients[c.Window.Id] = c
 	ws.AddClient(c)
+	tr.handleSessionTiling(c, ws, e)
+	tr.handleRuleTiling(c, ws)
 
 	// Attach handlers
 	tr.attachHandlers(c)
+	trackCallbacks(c)
 	tr.Tile(ws)
 
 	return true
@@ -276,6 +371,7 @@ func (tr *Tracker) untrackWindow(w xproto.Window) bool {
 	}
 
 	// Detach events
+	mousebind.Detach(store.X, w)
 	xevent.Detach(store.X, w)
 
 	// Restore client
@@ -291,8 +387,95 @@ func (tr *Tracker) untrackWindow(w xproto.Window) bool {
 	return true
 }
 
+func (tr *Tracker) handleSessionPlacement(c *store.Client, e *SessionEntry) {
+	if e == nil {
+		return
+	}
+	log.Debug("Client session handler fired [", c.Latest.Class, "]")
+
+	// Move client to recorded desktop
+	if e.Location.Desktop != c.Latest.Location.Desktop && e.Location.Desktop < store.Workplace.DesktopCount {
+		c.MoveToDesktop(uint32(e.Location.Desktop))
+		c.Latest.Location.Desktop = e.Location.Desktop
+	}
+
+	// Move client to recorded screen
+	if e.Location.Screen != c.Latest.Location.Screen && e.Location.Screen < store.Workplace.ScreenCount {
+		c.Latest.Location.Screen = e.Location.Screen
+	}
+}
+
+func (tr *Tracker) handleSessionTiling(c *store.Client, ws *Workspace, e *SessionEntry) {
+	if e == nil || c.IsFloating() || ws.Location != e.Location {
+		return
+	}
+
+	// Place client into recorded slot
+	for _, l := range ws.Layouts {
+		l.GetManager().PlaceClient(c, e.Master, e.Index)
+	}
+}
+
+func (tr *Tracker) handleRulePlacement(c *store.Client) {
+	if c.Rule == nil {
+		return
+	}
+	rule := c.Rule
+
+	// Move client to desktop
+	if rule.Desktop != nil && *rule.Desktop != c.Latest.Location.Desktop {
+		if *rule.Desktop < store.Workplace.DesktopCount {
+			c.MoveToDesktop(uint32(*rule.Desktop))
+			c.Latest.Location.Desktop = *rule.Desktop
+		} else {
+			log.Warn("Invalid rule desktop ", *rule.Desktop, " [", c.Latest.Class, "]")
+		}
+	}
+
+	// Move client to screen
+	if rule.Screen != nil && *rule.Screen != c.Latest.Location.Screen {
+		if *rule.Screen < store.Workplace.ScreenCount {
+			c.Latest.Location.Screen = *rule.Screen
+
+			// Center floating windows on screen
+			if c.IsFloating() {
+				dx, dy, dw, dh := store.DesktopGeometry(*rule.Screen).Pieces()
+				_, _, w, h := c.OuterGeometry()
+				c.MoveWindow(dx+dw/2-w/2, dy+dh/2-h/2, 0, 0)
+			}
+		} else {
+			log.Warn("Invalid rule screen ", *rule.Screen, " [", c.Latest.Class, "]")
+		}
+	}
+}
+
+func (tr *Tracker) handleRuleTiling(c *store.Client, ws *Workspace) {
+	if c.Rule == nil || c.IsFloating() {
+		return
+	}
+	rule := c.Rule
+
+	// Set client role
+	for _, l := range ws.Layouts {
+		mg := l.GetManager()
+		switch rule.Role {
+		case "master":
+			if !mg.IsMaster(c) {
+				mg.MakeMaster(c)
+			}
+		case "slave":
+			mg.MakeSlave(c)
+		}
+	}
+
+	// Set workspace layout
+	if len(rule.Layout) > 0 && !ws.SetLayoutByName(rule.Layout) {
+		log.Warn("Invalid rule layout ", rule.Layout, " [", c.Latest.Class, "]")
+	}
+}
+
 func (tr *Tracker) handleMaximizedClient(c *store.Client) {
-	if !tr.isTracked(c.Window.Id) {
+	if !tr.isTracked(c.Window.Id) || c.IsFloating() {
 		return
 	}
 
@@ -319,7 +502,7 @@ func (tr *Tracker) handleMaximizedClient(c *store.Client) {
 }
 
 func (tr *Tracker) handleMinimizedClient(c *store.Client) {
-	if !tr.isTracked(c.Window.Id) {
+	if !tr.isTracked(c.Window.Id) || c.IsScratchpad() {
 		return
 	}
 
@@ -338,7 +521,7 @@ func (tr *Tracker) handleMinimizedClient(c *store.Client) {
 
 func (tr *Tracker) handleResizeClient(c *store.Client) {
 	ws := tr.ClientWorkspace(c)
-	if ws.TilingDisabled() || !tr.isTracked(c.Window.Id) || store.IsMaximized(store.GetInfo(c.Window.Id)) {
+	if ws.TilingDisabled() || !tr.isTracked(c.Window.Id) || c.IsFloating() || store.IsMaximized(store.GetInfo(c.Window.Id)) {
 		return
 	}
 
@@ -381,7 +564,8 @@ func (tr *Tracker) handleResizeClient(c *store.Client) {
 				Bottom: cy == py && ch != ph,
 				Left:   cx != px,
 			}
-			ws.ActiveLayout().UpdateProportions(c, dir)
+			ox, oy, ow, oh := c.OuterGeometry()
+			ws.ActiveLayout().UpdateProportions(c, &common.Geometry{X: ox, Y: oy, Width: ow, Height: oh}, dir)
 		}
 
 		// Tile workspace
@@ -391,7 +575,8 @@ func (tr *Tracker) handleResizeClient(c *store.Client) {
 
 func (tr *Tracker) handleMoveClient(c *store.Client) {
 	ws := tr.ClientWorkspace(c)
-	if !tr.isTracked(c.Window.Id) || store.IsMaximized(store.GetInfo(c.Window.Id)) {
+	tr.handleSnapPreview(c, ws)
+	if !tr.isTracked(c.Window.Id) || c.IsFloating() || store.IsMaximized(store.GetInfo(c.Window.Id)) {
 		return
 	}
 
@@ -443,6 +628,60 @@ func (tr *Tracker) handleMoveClient(c *store.Client) {
 	}
 }
 
+func (tr *Tracker) handleSnapPreview(c *store.Client, ws *Workspace) {
+	if !common.Config.EdgeSnap || ws == nil || !tr.isTracked(c.Window.Id) || store.IsMaximized(store.GetInfo(c.Window.Id)) {
+		return
+	}
+
+	// Snap floating clients or clients on disabled workspaces
+	if !c.IsFloating() && ws.TilingEnabled() {
+		return
+	}
+
+	// Ignore moves without pressed pointer
+	pt := store.PointerUpdate(store.X)
+	if !pt.Pressed() {
+		return
+	}
+
+	// Obtain snap slot under pointer
+	previous, _ := tr.Handlers.SnapClient.Target.(*store.Snap)
+	current := store.SnapAt(pt.Position)
+	if previous == nil && current == nil {
+		return
+	}
+	if previous != nil && current != nil && previous.Name == current.Name && previous.Screen == current.Screen {
+		return
+	}
+
+	// Set client snap event
+	tr.Handlers.SnapClient.Reset()
+	if current != nil {
+		tr.Handlers.SnapClient = &Handler{Dragging: true, Source: c, Target: current}
+		log.Debug("Client snap handler active [", c.Latest.Class, "-", current.Name, "]")
+	}
+
+	// Communicate snap change
+	tr.Channels.Event <- "snap_change"
+}
+
+func (tr *Tracker) handleSnapClient(h *Handler) {
+	c, target := h.Source.(*store.Client), h.Target.(*store.Snap)
+	if !tr.isTracked(c.Window.Id) {
+		return
+	}
+	log.Debug("Client snap handler fired [", c.Latest.Class, "-", target.Name, "]")
+
+	// Move client into snap slot
+	c.MoveWindow(target.Geometry.Pieces())
+
+	// Reset client snapping handler
+	h.Reset()
+
+	// Communicate snap change
+	tr.Channels.Event <- "snap_change"
+}
+
 func (tr *Tracker) handleSwapClient(h *Handler) {
 	c, target := h.Source.(*store.Client), h.Target.(*store.Client)
 	ws := tr.ClientWorkspace(c)
@@ -573,6 +812,11 @@ func (tr *Tracker) onPointerUpdate(pointer store.XPointer, desktop uint, screen
 			tr.handleWorkspaceChange(tr.Handlers.SwapScreen)
 		}
 
+		// Window moved to screen edge
+		if tr.Handlers.SnapClient.Active() && buttonReleased {
+			tr.handleSnapClient(tr.Handlers.SnapClient)
+		}
+
 		// Window moved over another window
 		if tr.Handlers.SwapClient.Active() {
 			tr.handleSwapClient(tr.Handlers.SwapClient)
@@ -620,10 +864,30 @@ func (tr *Tracker) attachHandlers(c *store.Client) {
 			tr.handleMinimizedClient(c)
 		} else if aname == "_NET_WM_DESKTOP" {
 			tr.handleWorkspaceChange(&Handler{Source: c, Target: tr.ActiveWorkspace()})
+		} else if common.IsInList(aname, []string{"_NET_WM_NAME", "WM_NAME"}) {
+			c.Update()
+
+			// Communicate title change of visible titles
+			ws := tr.ClientWorkspace(c)
+			if c.Window.Id == store.Windows.Active.Id || (ws != nil && ws.ActiveLayout().GetName() == "tabbed") {
+				tr.Channels.Event <- "title_change"
+			}
 		}
 	}).Connect(store.X, c.Window.Id)
 }
 
+func OnTrack(fun func(*store.Client)) {
+	trackCallbacksFun = append(trackCallbacksFun, fun)
+}
+
+func trackCallbacks(c *store.Client) {
+	log.Debug("Track event [", c.Latest.Class, "]")
+
+	for _, fun := range trackCallbacksFun {
+		fun(c)
+	}
+}
+
 func (tr *Tracker) isTracked(w xproto.Window) bool {
 	_, ok := tr.Clients[w]
 	return ok
@@ -631,5 +895,11 @@ func (tr *Tracker) isTracked(w xproto.Window) bool {
 
 func (tr *Tracker) isTrackable(w xproto.Window) bool {
 	info := store.GetInfo(w)
+
+	// Keep hidden scratchpads tracked
+	if c, ok := tr.Clients[w]; ok && c.IsScratchpad() {
+		return !store.IsIgnored(info)
+	}
+
 	return !store.IsSpecial(info) && !store.IsIgnored(info)
 }
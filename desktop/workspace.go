@@ -15,11 +15,12 @@ import (
 )
 
 type Workspace struct {
-	Name     string         // Workspace location name
-	Location store.Location // Desktop and screen location
-	Layouts  []Layout       // List of available layouts
-	Layout   uint           // Active layout index
-	Tiling   bool           // Tiling is enabled
+	Name     string          // Workspace location name
+	Location store.Location  // Desktop and screen location
+	Layouts  []Layout        // List of available layouts
+	Layout   uint            // Active layout index
+	Tiling   bool            // Tiling is enabled
+	Floating []*store.Client `json:"-"` // List of floating clients
 }
 
 func CreateWorkspaces() map[store.Location]*Workspace {
@@ -72,14 +73,33 @@ func CreateWorkspaces() map[store.Location]*Workspace {
 }
 
 func CreateLayouts(loc store.Location) []Layout {
-	return []Layout{
+	layouts := []Layout{
 		layout.CreateVerticalLeftLayout(loc),
 		layout.CreateVerticalRightLayout(loc),
 		layout.CreateHorizontalTopLayout(loc),
 		layout.CreateHorizontalBottomLayout(loc),
 		layout.CreateMaximizedLayout(loc),
 		layout.CreateFullscreenLayout(loc),
+		layout.CreateDwindleLayout(loc),
+		layout.CreateVerticalCenterLayout(loc),
+		layout.CreateGridLayout(loc),
+		layout.CreateTabbedLayout(loc),
 	}
+
+	// Append user defined layouts
+	for _, dl := range layout.CreateDeclarativeLayouts(loc) {
+		duplicate := false
+		for _, l := range layouts {
+			duplicate = duplicate || l.GetName() == dl.GetName()
+		}
+		if duplicate {
+			log.Warn("Error creating layout ", dl.GetName(), ": name already in use")
+			continue
+		}
+		layouts = append(layouts, dl)
+	}
+
+	return layouts
 }
 
 func (ws *Workspace) EnableTiling() {
@@ -109,9 +129,22 @@ func (ws *Workspace) ActiveLayout() Layout {
 }
 
 func (ws *Workspace) SetLayout(layout uint) {
+	if layout >= uint(len(ws.Layouts)) {
+		return
+	}
 	ws.Layout = layout
 }
 
+func (ws *Workspace) SetLayoutByName(name string) bool {
+	for i, l := range ws.Layouts {
+		if l.GetName() == name {
+			ws.SetLayout(uint(i))
+			return true
+		}
+	}
+	return false
+}
+
 func (ws *Workspace) ResetLayouts() {
 
 	// Reset layouts
@@ -162,6 +195,18 @@ func (ws *Workspace) CycleLayout(dir int) {
 }
 
 func (ws *Workspace) AddClient(c *store.Client) {
+	if c.IsFloating() {
+		log.Info("Add floating client [", c.Latest.Class, "]")
+
+		// Add client to floating list
+		for _, fc := range ws.Floating {
+			if fc.Window.Id == c.Window.Id {
+				return
+			}
+		}
+		ws.Floating = append(ws.Floating, c)
+		return
+	}
 	log.Info("Add client for each layout [", c.Latest.Class, "]")
 
 	// Add client to all layouts
@@ -177,6 +222,14 @@ func (ws *Workspace) RemoveClient(c *store.Client) {
 	for _, l := range ws.Layouts {
 		l.RemoveClient(c)
 	}
+
+	// Remove client from floating list
+	for i, fc := range ws.Floating {
+		if fc.Window.Id == c.Window.Id {
+			ws.Floating = append(ws.Floating[:i], ws.Floating[i+1:]...)
+			break
+		}
+	}
 }
 
 func (ws *Workspace) VisibleClients() []*store.Client {
@@ -185,9 +238,12 @@ func (ws *Workspace) VisibleClients() []*store.Client {
 
 	// Obtain visible clients
 	clients := mg.Clients(store.Visible)
-	if common.IsInList(al.GetName(), []string{"maximized", "fullscreen"}) {
+	if common.IsInList(al.GetName(), []string{"maximized", "fullscreen", "tabbed"}) {
 		clients = mg.Visible(&store.Clients{Stacked: mg.Clients(store.Stacked), Maximum: 1})
 	}
+	if al.GetName() == "grid" {
+		clients = mg.Clients(store.Stacked)
+	}
 
 	return clients
 }
@@ -277,7 +333,7 @@ func (ws *Workspace) Read() *Workspace {
 	}
 
 	// Parse workspace cache
-	cached := &Workspace{Layouts: CreateLayouts(ws.Location)}
+	cached := &Workspace{Layouts: CreateLayouts(ws.Location), Layout: ws.Layout}
 	err = json.Unmarshal([]byte(data), &cached)
 	if err != nil {
 		log.Warn("Error reading workspace cache [", ws.Name, "]")
@@ -289,6 +345,43 @@ func (ws *Workspace) Read() *Workspace {
 	return cached
 }
 
+func (ws *Workspace) UnmarshalJSON(data []byte) error {
+	var cached struct {
+		Name     string            // Workspace location name
+		Location store.Location    // Desktop and screen location
+		Layouts  []json.RawMessage // List of cached layouts
+		Layout   uint              // Active layout index
+		Tiling   bool              // Tiling is enabled
+	}
+	err := json.Unmarshal(data, &cached)
+	if err != nil {
+		return err
+	}
+	ws.Name, ws.Location, ws.Tiling = cached.Name, cached.Location, cached.Tiling
+
+	// Decode cached layouts by name
+	for i, raw := range cached.Layouts {
+		var named struct{ Name string }
+		if json.Unmarshal(raw, &named) != nil {
+			continue
+		}
+		for j, l := range ws.Layouts {
+			if l.GetName() != named.Name {
+				continue
+			}
+			err = json.Unmarshal(raw, l)
+			if err != nil {
+				return err
+			}
+			if uint(i) == cached.Layout {
+				ws.Layout = uint(j)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (ws *Workspace) Cache() common.Cache[*Workspace] {
 	subfolder := fmt.Sprintf("workspace-%d", ws.Location.Desktop)
 	filename := fmt.Sprintf("%s-%d", subfolder, ws.Location.Screen)
@@ -2,6 +2,7 @@ package input
 
 import (
 	"os"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -20,6 +21,7 @@ import (
 
 var (
 	executeCallbacksFun []func(string, uint, uint) // Execute events callback functions
+	eventCallbacksFun   []func(string)             // Tracker events callback functions
 )
 
 func Bind(tr *desktop.Tracker) {
@@ -28,19 +30,41 @@ func Bind(tr *desktop.Tracker) {
 	BindKeys(tr)
 	BindTray(tr)
 	BindDbus(tr)
+	BindIpc(tr)
 	BindAddons(tr)
+	BindTabs(tr)
+	BindBar(tr)
 }
 
 func ExecuteAction(action string, tr *desktop.Tracker, ws *desktop.Workspace) bool {
-	success := false
 	if len(action) == 0 || tr == nil || ws == nil {
 		return false
 	}
 
+	// Parse action sequence
+	actions, err := common.ParseActions(action)
+	if err != nil {
+		log.Warn("Error parsing action \"", action, "\": ", err)
+		return false
+	}
+
+	// Execute actions in order
+	results := []bool{}
+	for _, a := range actions {
+		results = append(results, executeAction(a, tr, ws))
+	}
+
+	return len(results) > 0 && common.AllTrue(results)
+}
+
+func executeAction(a common.Action, tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	success := false
+	action := a.String()
+
 	log.Info("Execute action ", action, " [", ws.Name, "]")
 
 	// Choose action command
-	switch action {
+	switch a.Name {
 	case "enable":
 		success = EnableTiling(tr, ws)
 	case "disable":
@@ -85,6 +109,24 @@ func ExecuteAction(action string, tr *desktop.Tracker, ws *desktop.Workspace) bo
 		success = NextScreen(tr, ws)
 	case "screen_previous":
 		success = PreviousScreen(tr, ws)
+	case "focus_left":
+		success = FocusWindow(tr, ws, "left")
+	case "focus_right":
+		success = FocusWindow(tr, ws, "right")
+	case "focus_up":
+		success = FocusWindow(tr, ws, "up")
+	case "focus_down":
+		success = FocusWindow(tr, ws, "down")
+	case "swap_left":
+		success = SwapWindow(tr, ws, "left")
+	case "swap_right":
+		success = SwapWindow(tr, ws, "right")
+	case "swap_up":
+		success = SwapWindow(tr, ws, "up")
+	case "swap_down":
+		success = SwapWindow(tr, ws, "down")
+	case "window_float":
+		success = ToggleFloating(tr, ws)
 	case "master_make":
 		success = MakeMaster(tr, ws)
 	case "master_make_next":
@@ -95,12 +137,40 @@ func ExecuteAction(action string, tr *desktop.Tracker, ws *desktop.Workspace) bo
 		success = IncreaseProportion(tr, ws)
 	case "proportion_decrease":
 		success = DecreaseProportion(tr, ws)
+	case "proportion_set":
+		success = SetProportion(tr, ws, a.Float())
+	case "master_count":
+		success = SetMasterCount(tr, ws, a.Int())
+	case "slave_count":
+		success = SetSlaveCount(tr, ws, a.Int())
+	case "desktop_switch":
+		success = SwitchDesktop(tr, a.Int())
+	case "hints":
+		success = EnterHints(tr)
+	case "session_restore":
+		success = RestoreSession(tr)
 	case "restart":
 		success = Restart(tr)
 	case "exit":
 		success = Exit(tr)
 	default:
-		success = External(action)
+		if strings.HasPrefix(action, "scratchpad_set") {
+			success = SetScratchpad(tr, ws, actionName(action, "scratchpad_set"))
+		} else if strings.HasPrefix(action, "scratchpad_toggle") {
+			success = ToggleScratchpad(tr, ws, actionName(action, "scratchpad_toggle"))
+		} else if strings.HasPrefix(action, "profile_save") {
+			success = SaveProfile(tr, ws, actionName(action, "profile_save"))
+		} else if strings.HasPrefix(action, "profile_load") {
+			success = LoadProfile(tr, ws, actionName(action, "profile_load"))
+		} else if strings.HasPrefix(action, "mode_") {
+			success = EnterMode(tr, strings.TrimPrefix(action, "mode_"))
+		} else if strings.HasPrefix(action, "desktop_") {
+			success = MoveDesktop(tr, ws, strings.TrimPrefix(action, "desktop_"))
+		} else if strings.HasPrefix(action, "layout_") && isNamedLayout(ws, strings.TrimPrefix(action, "layout_")) {
+			success = NamedLayout(tr, ws, strings.TrimPrefix(action, "layout_"))
+		} else {
+			success = External(a.Command)
+		}
 	}
 	time.AfterFunc(100*time.Millisecond, tr.Handlers.Reset)
 
@@ -124,23 +194,39 @@ func ExecuteActions(action string, tr *desktop.Tracker, mod string) bool {
 		active = client
 	}
 
-	// Execute actions per workspace
+	// Parse action sequence
+	actions, err := common.ParseActions(action)
+	if err != nil {
+		log.Warn("Error parsing action \"", action, "\": ", err)
+		return false
+	}
+
 	results := []bool{}
-	for _, ws := range tr.Workspaces {
+	for _, a := range actions {
 
-		// Execute only on active screen
-		if mod == "current" && ws.Location != active.Location {
+		// Execute global actions only once
+		if a.IsGlobal() {
+			results = append(results, ExecuteAction(a.Command, tr, active))
 			continue
 		}
 
-		// Execute only on active workspace
-		if mod == "screens" && (ws.Location.Desktop != active.Location.Desktop) {
-			continue
-		}
+		// Execute workspace actions per workspace
+		for _, ws := range tr.Workspaces {
+
+			// Execute only on active screen
+			if mod == "current" && ws.Location != active.Location {
+				continue
+			}
+
+			// Execute only on active workspace
+			if mod == "screens" && (ws.Location.Desktop != active.Location.Desktop) {
+				continue
+			}
 
-		// Execute action and store results
-		success := ExecuteAction(action, tr, ws)
-		results = append(results, success)
+			// Execute action and store results
+			success := ExecuteAction(a.Command, tr, ws)
+			results = append(results, success)
+		}
 	}
 
 	return common.AllTrue(results)
@@ -371,6 +457,84 @@ func FullscreenLayout(tr *desktop.Tracker, ws *desktop.Workspace) bool {
 	return true
 }
 
+func NamedLayout(tr *desktop.Tracker, ws *desktop.Workspace, name string) bool {
+	if ws.TilingDisabled() {
+		return false
+	}
+	success := false
+	for _, l := range ws.Layouts {
+		if strings.ReplaceAll(l.GetName(), "-", "_") == strings.ReplaceAll(name, "-", "_") {
+			success = ws.SetLayoutByName(l.GetName())
+		}
+	}
+	if !success {
+		return false
+	}
+	tr.Tile(ws)
+
+	ui.ShowLayout(ws)
+	ui.UpdateIcon(ws)
+
+	return true
+}
+
+func isNamedLayout(ws *desktop.Workspace, name string) bool {
+	for _, l := range ws.Layouts {
+		if strings.ReplaceAll(l.GetName(), "-", "_") == strings.ReplaceAll(name, "-", "_") {
+			return true
+		}
+	}
+	return false
+}
+
+func SetMasterCount(tr *desktop.Tracker, ws *desktop.Workspace, count int) bool {
+	if ws.TilingDisabled() {
+		return false
+	}
+	al := ws.ActiveLayout()
+	mg := al.GetManager()
+
+	// Change number of masters until count is reached
+	for previous := -1; mg.Masters.Maximum != count && mg.Masters.Maximum != previous; {
+		previous = mg.Masters.Maximum
+		if mg.Masters.Maximum < count {
+			al.IncreaseMaster()
+		} else {
+			al.DecreaseMaster()
+		}
+	}
+	tr.Tile(ws)
+
+	ui.ShowLayout(ws)
+	ui.UpdateIcon(ws)
+
+	return mg.Masters.Maximum == count
+}
+
+func SetSlaveCount(tr *desktop.Tracker, ws *desktop.Workspace, count int) bool {
+	if ws.TilingDisabled() {
+		return false
+	}
+	al := ws.ActiveLayout()
+	mg := al.GetManager()
+
+	// Change number of slaves until count is reached
+	for previous := -1; mg.Slaves.Maximum != count && mg.Slaves.Maximum != previous; {
+		previous = mg.Slaves.Maximum
+		if mg.Slaves.Maximum < count {
+			al.IncreaseSlave()
+		} else {
+			al.DecreaseSlave()
+		}
+	}
+	tr.Tile(ws)
+
+	ui.ShowLayout(ws)
+	ui.UpdateIcon(ws)
+
+	return mg.Slaves.Maximum == count
+}
+
 func IncreaseSlave(tr *desktop.Tracker, ws *desktop.Workspace) bool {
 	if ws.TilingDisabled() {
 		return false
@@ -445,6 +609,229 @@ func PreviousWindow(tr *desktop.Tracker, ws *desktop.Workspace) bool {
 	return true
 }
 
+func FocusWindow(tr *desktop.Tracker, ws *desktop.Workspace, direction string) bool {
+	c := tr.ActiveClient()
+	if c == nil || tr.ClientWorkspace(c) != ws {
+		return false
+	}
+
+	// Focus nearest client on current screen
+	co := tr.ClientInDirection(c, ws, direction)
+	if co == nil {
+
+		// Focus nearest client on adjacent screen
+		screen, ok := store.ScreenInDirection(c.Latest.Location.Screen, direction)
+		if !ok {
+			return false
+		}
+		co = tr.ClientInDirection(c, tr.WorkspaceAt(ws.Location.Desktop, screen), direction)
+	}
+	if co == nil {
+		return false
+	}
+
+	store.ActiveWindowSet(store.X, co.Window)
+
+	return true
+}
+
+func SwapWindow(tr *desktop.Tracker, ws *desktop.Workspace, direction string) bool {
+	c := tr.ActiveClient()
+	if c == nil || tr.ClientWorkspace(c) != ws || c.IsFloating() {
+		return false
+	}
+
+	// Swap with nearest client on current screen
+	if co := tr.ClientInDirection(c, ws, direction); co != nil {
+		if ws.TilingDisabled() {
+			return false
+		}
+		ws.ActiveLayout().GetManager().SwapClient(c, co)
+		tr.Tile(ws)
+
+		return true
+	}
+
+	// Move client to adjacent screen
+	screen, ok := store.ScreenInDirection(c.Latest.Location.Screen, direction)
+	if !ok {
+		return false
+	}
+
+	return c.MoveToScreen(uint32(screen))
+}
+
+func MoveDesktop(tr *desktop.Tracker, ws *desktop.Workspace, target string) bool {
+	c := tr.ActiveClient()
+	if c == nil || tr.ClientWorkspace(c) != ws {
+		return false
+	}
+
+	// Obtain follow modifier
+	follow := strings.HasSuffix(target, "_follow")
+	target = strings.TrimSuffix(target, "_follow")
+
+	// Obtain target desktop with wrap around
+	count := int(store.Workplace.DesktopCount)
+	desktop := int(c.Latest.Location.Desktop)
+	switch target {
+	case "next":
+		desktop = (desktop + 1) % count
+	case "previous":
+		desktop = (desktop - 1 + count) % count
+	default:
+		index, err := strconv.Atoi(target)
+		if err != nil || index < 0 || index >= count {
+			return false
+		}
+		desktop = index
+	}
+	if uint(desktop) == c.Latest.Location.Desktop {
+		return false
+	}
+
+	// Move client to desktop
+	c.MoveToDesktop(uint32(desktop))
+
+	// Switch to desktop and activate client
+	if follow {
+		store.CurrentDesktopSet(store.X, uint(desktop))
+		store.ActiveWindowSet(store.X, c.Window)
+	}
+
+	return true
+}
+
+func ToggleFloating(tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	c := tr.ActiveClient()
+	if c == nil || tr.ClientWorkspace(c) != ws || c.IsScratchpad() {
+		return false
+	}
+
+	// Toggle client floating state
+	ws.RemoveClient(c)
+	c.Floating = !c.Floating
+	ws.AddClient(c)
+	c.Write()
+
+	// Release size limits of floating client
+	if c.IsFloating() {
+		c.UnLimit()
+	}
+	tr.Tile(ws)
+
+	ui.ShowLayout(ws)
+
+	return true
+}
+
+func SetScratchpad(tr *desktop.Tracker, ws *desktop.Workspace, name string) bool {
+	c := tr.ActiveClient()
+	if c == nil || tr.ClientWorkspace(c) != ws {
+		return false
+	}
+
+	// Release previous scratchpad client
+	if sc := tr.ScratchpadClient(name); sc != nil && sc != c {
+		sws := tr.ClientWorkspace(sc)
+		if sws != nil {
+			sws.RemoveClient(sc)
+		}
+		sc.Scratchpad = ""
+		if sws != nil {
+			sws.AddClient(sc)
+			tr.Tile(sws)
+		}
+		sc.UnHide()
+	}
+
+	// Float and hide scratchpad client
+	ws.RemoveClient(c)
+	c.Scratchpad = name
+	ws.AddClient(c)
+	c.UnLimit()
+	c.Hide()
+	tr.Tile(ws)
+
+	// Communicate scratchpads change
+	tr.Channels.Event <- "scratchpads_change"
+
+	return true
+}
+
+func ToggleScratchpad(tr *desktop.Tracker, ws *desktop.Workspace, name string) bool {
+	c := tr.ScratchpadClient(name)
+	if c == nil {
+		return false
+	}
+
+	// Hide visible scratchpad on current workspace
+	hidden := store.IsMinimized(store.GetInfo(c.Window.Id))
+	if !hidden && tr.ClientWorkspace(c) == ws {
+		c.Hide()
+
+		// Communicate scratchpads change
+		tr.Channels.Event <- "scratchpads_change"
+
+		return true
+	}
+
+	// Move scratchpad client to current workspace, location is updated by the window events
+	if cws := tr.ClientWorkspace(c); cws != nil {
+		cws.RemoveClient(c)
+	}
+	if c.Latest.Location.Desktop != ws.Location.Desktop {
+		c.MoveToDesktop(uint32(ws.Location.Desktop))
+	}
+	ws.AddClient(c)
+
+	// Center scratchpad client on screen
+	dx, dy, dw, dh := store.DesktopGeometry(ws.Location.Screen).Pieces()
+	_, _, w, h := c.OuterGeometry()
+	c.MoveWindow(dx+dw/2-w/2, dy+dh/2-h/2, 0, 0)
+
+	// Unhide and activate scratchpad client
+	c.UnHide()
+	store.ActiveWindowSet(store.X, c.Window)
+
+	// Communicate scratchpads change
+	tr.Channels.Event <- "scratchpads_change"
+
+	return true
+}
+
+func SaveProfile(tr *desktop.Tracker, ws *desktop.Workspace, name string) bool {
+	return desktop.CreateProfile(name, tr.Workspaces).Write()
+}
+
+func LoadProfile(tr *desktop.Tracker, ws *desktop.Workspace, name string) bool {
+	p := desktop.ReadProfile(name)
+	if p == nil {
+		return false
+	}
+
+	// Apply profile to all workspaces
+	tilings := make(map[store.Location]bool)
+	for location, w := range tr.Workspaces {
+		tilings[location] = w.TilingEnabled()
+	}
+	p.Apply(tr.Workspaces)
+
+	// Tile or restore workspaces
+	for location, w := range tr.Workspaces {
+		if w.TilingEnabled() {
+			tr.Tile(w)
+		} else if tilings[location] {
+			tr.Restore(w, store.Latest)
+		}
+	}
+
+	ui.ShowLayout(ws)
+	ui.UpdateIcon(ws)
+
+	return true
+}
+
 func NextScreen(tr *desktop.Tracker, ws *desktop.Workspace) bool {
 	c := tr.ActiveClient()
 	if c == nil {
@@ -528,6 +915,16 @@ func IncreaseProportion(tr *desktop.Tracker, ws *desktop.Workspace) bool {
 	return true
 }
 
+func SetProportion(tr *desktop.Tracker, ws *desktop.Workspace, proportion float64) bool {
+	if ws.TilingDisabled() {
+		return false
+	}
+	ws.ActiveLayout().SetProportion(proportion)
+	tr.Tile(ws)
+
+	return true
+}
+
 func DecreaseProportion(tr *desktop.Tracker, ws *desktop.Workspace) bool {
 	if ws.TilingDisabled() {
 		return false
@@ -538,8 +935,27 @@ func DecreaseProportion(tr *desktop.Tracker, ws *desktop.Workspace) bool {
 	return true
 }
 
+func SwitchDesktop(tr *desktop.Tracker, desktop int) bool {
+	if desktop < 0 || uint(desktop) >= store.Workplace.DesktopCount {
+		return false
+	}
+
+	store.CurrentDesktopSet(store.X, uint(desktop))
+
+	return true
+}
+
+func RestoreSession(tr *desktop.Tracker) bool {
+	if tr.Session == nil {
+		log.Warn("Restoring session disabled")
+		return false
+	}
+	return tr.Session.Restore()
+}
+
 func Restart(tr *desktop.Tracker) bool {
 	tr.Write()
+	tr.WriteSession(0)
 
 	xevent.Detach(store.X, store.X.RootWin())
 
@@ -564,6 +980,7 @@ func Restart(tr *desktop.Tracker) bool {
 
 func Exit(tr *desktop.Tracker) bool {
 	tr.Write()
+	tr.WriteSession(0)
 
 	xevent.Detach(store.X, store.X.RootWin())
 
@@ -587,7 +1004,10 @@ func Exit(tr *desktop.Tracker) bool {
 }
 
 func External(command string) bool {
-	params := strings.Split(command, " ")
+	params := common.SplitFields(command)
+	if len(params) == 0 {
+		return false
+	}
 
 	if !common.HasFlag("enable-external-commands") {
 		log.Warn("Executing external command \"", params[0], "\" disabled")
@@ -606,6 +1026,14 @@ func External(command string) bool {
 	return true
 }
 
+func actionName(action string, prefix string) string {
+	name := strings.TrimLeft(strings.TrimPrefix(action, prefix), "_ ")
+	if len(name) == 0 {
+		return "default"
+	}
+	return name
+}
+
 func OnExecute(fun func(string, uint, uint)) {
 	executeCallbacksFun = append(executeCallbacksFun, fun)
 }
@@ -617,3 +1045,15 @@ func executeCallbacks(action string, desktop uint, screen uint) {
 		fun(action, desktop, screen)
 	}
 }
+
+func OnEvent(fun func(string)) {
+	eventCallbacksFun = append(eventCallbacksFun, fun)
+}
+
+func eventCallbacks(event string) {
+	log.Debug("Tracker event ", event)
+
+	for _, fun := range eventCallbacksFun {
+		fun(event)
+	}
+}
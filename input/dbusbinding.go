@@ -2,6 +2,7 @@ package input
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"reflect"
 	"sort"
@@ -116,6 +117,44 @@ func (m Methods) WindowToScreen(id int32, screen int32) (string, *dbus.Error) {
 	return dataMap("Result", "WindowToScreen", result), nil
 }
 
+func (m Methods) ScratchpadSet(name string) (string, *dbus.Error) {
+	success := false
+
+	// Set active client as scratchpad
+	ws := m.Tracker.ClientWorkspace(m.Tracker.ActiveClient())
+	if ws != nil {
+		success = ExecuteAction("scratchpad_set_"+name, m.Tracker, ws)
+	}
+
+	// Return result
+	result := common.Map{"Success": success}
+
+	return dataMap("Result", "ScratchpadSet", result), nil
+}
+
+func (m Methods) ScratchpadToggle(name string) (string, *dbus.Error) {
+	success := false
+
+	// Toggle scratchpad on active workspace
+	ws := m.Tracker.ActiveWorkspace()
+	if ws != nil {
+		success = ExecuteAction("scratchpad_toggle_"+name, m.Tracker, ws)
+	}
+
+	// Return result
+	result := common.Map{"Success": success}
+
+	return dataMap("Result", "ScratchpadToggle", result), nil
+}
+
+func (m Methods) ProfileList() (string, *dbus.Error) {
+
+	// Return result
+	result := common.Map{"Values": desktop.ProfileNames()}
+
+	return dataMap("Result", "ProfileList", result), nil
+}
+
 func (m Methods) DesktopSwitch(desktop int32) (string, *dbus.Error) {
 	success := false
 
@@ -132,6 +171,360 @@ func (m Methods) DesktopSwitch(desktop int32) (string, *dbus.Error) {
 	return dataMap("Result", "DesktopSwitch", result), nil
 }
 
+func (m Methods) LayoutSet(desktop int32, screen int32, name string) (string, *dbus.Error) {
+	ws, result := m.workspace(desktop, screen)
+	if ws == nil {
+		return dataMap("Result", "LayoutSet", result), nil
+	}
+
+	// Validate layout name
+	valid := false
+	for _, l := range ws.Layouts {
+		valid = valid || l.GetName() == name
+	}
+	if !valid {
+		result = failure("InvalidLayout", "layout %s does not exist", name)
+		return dataMap("Result", "LayoutSet", result), nil
+	}
+
+	// Set layout
+	result = outcome(NamedLayout(m.Tracker, ws, name), "TilingDisabled", "tiling is disabled on workspace")
+
+	return dataMap("Result", "LayoutSet", result), nil
+}
+
+func (m Methods) ProportionsSet(desktop int32, screen int32, kind string, values []float64) (string, *dbus.Error) {
+	ws, result := m.workspace(desktop, screen)
+	if ws == nil {
+		return dataMap("Result", "ProportionsSet", result), nil
+	}
+
+	// Validate proportion kind
+	ps := ws.ActiveLayout().GetManager().Proportions
+	proportions, ok := map[string]map[int][]float64{
+		"master_slave":  ps.MasterSlave,
+		"master_master": ps.MasterMaster,
+		"slave_slave":   ps.SlaveSlave,
+		"splits":        ps.Splits,
+		"columns":       ps.Columns,
+		"rows":          ps.Rows,
+	}[kind]
+	if !ok {
+		result = failure("InvalidKind", "proportion kind %s does not exist", kind)
+		return dataMap("Result", "ProportionsSet", result), nil
+	}
+
+	// Validate proportion values
+	for _, value := range values {
+		if value < common.Config.ProportionMin || value > 1.0-common.Config.ProportionMin {
+			result = failure("InvalidValues", "proportion %g is out of range [%g, %g]", value, common.Config.ProportionMin, 1.0-common.Config.ProportionMin)
+			return dataMap("Result", "ProportionsSet", result), nil
+		}
+	}
+	if kind == "splits" {
+
+		// Splits are keyed by index, with one first child proportion per split
+		if len(values) == 0 {
+			result = failure("InvalidValues", "no proportions given for %s", kind)
+			return dataMap("Result", "ProportionsSet", result), nil
+		}
+		for i := range values {
+			if p, ok := proportions[i]; !ok || len(p) != 2 {
+				result = failure("InvalidValues", "split %d does not exist", i)
+				return dataMap("Result", "ProportionsSet", result), nil
+			}
+		}
+	} else {
+
+		// Proportions are keyed by count and sum up to one
+		if _, ok := proportions[len(values)]; !ok || len(values) < 2 {
+			result = failure("InvalidValues", "%d proportions are not supported for %s", len(values), kind)
+			return dataMap("Result", "ProportionsSet", result), nil
+		}
+		sum := 0.0
+		for _, value := range values {
+			sum += value
+		}
+		if math.Abs(sum-1.0) > 0.001 {
+			result = failure("InvalidValues", "proportions sum up to %g instead of 1.0", sum)
+			return dataMap("Result", "ProportionsSet", result), nil
+		}
+	}
+	if ws.TilingDisabled() {
+		result = failure("TilingDisabled", "tiling is disabled on workspace")
+		return dataMap("Result", "ProportionsSet", result), nil
+	}
+
+	// Set proportions
+	if kind == "splits" {
+		for i, value := range values {
+			proportions[i][0], proportions[i][1] = value, 1.0-value
+		}
+	} else {
+		copy(proportions[len(values)], values)
+	}
+	m.Tracker.Tile(ws)
+
+	return dataMap("Result", "ProportionsSet", common.Map{"Success": true}), nil
+}
+
+func (m Methods) MastersSet(desktop int32, screen int32, count int32) (string, *dbus.Error) {
+	ws, result := m.workspace(desktop, screen)
+	if ws == nil {
+		return dataMap("Result", "MastersSet", result), nil
+	}
+
+	// Validate master count
+	if count < 0 || int(count) > common.Config.WindowMastersMax {
+		result = failure("InvalidCount", "master count %d is out of range [0, %d]", count, common.Config.WindowMastersMax)
+		return dataMap("Result", "MastersSet", result), nil
+	}
+
+	if ws.TilingDisabled() {
+		result = failure("TilingDisabled", "tiling is disabled on workspace")
+		return dataMap("Result", "MastersSet", result), nil
+	}
+
+	// Set master count
+	result = outcome(SetMasterCount(m.Tracker, ws, int(count)), "Failed", "master count %d could not be reached", count)
+
+	return dataMap("Result", "MastersSet", result), nil
+}
+
+func (m Methods) SlavesSet(desktop int32, screen int32, count int32) (string, *dbus.Error) {
+	ws, result := m.workspace(desktop, screen)
+	if ws == nil {
+		return dataMap("Result", "SlavesSet", result), nil
+	}
+
+	// Validate slave count
+	if count < 1 || int(count) > common.Config.WindowSlavesMax {
+		result = failure("InvalidCount", "slave count %d is out of range [1, %d]", count, common.Config.WindowSlavesMax)
+		return dataMap("Result", "SlavesSet", result), nil
+	}
+
+	if ws.TilingDisabled() {
+		result = failure("TilingDisabled", "tiling is disabled on workspace")
+		return dataMap("Result", "SlavesSet", result), nil
+	}
+
+	// Set slave count
+	result = outcome(SetSlaveCount(m.Tracker, ws, int(count)), "Failed", "slave count %d could not be reached", count)
+
+	return dataMap("Result", "SlavesSet", result), nil
+}
+
+func (m Methods) ClientSwap(id1 int32, id2 int32) (string, *dbus.Error) {
+	c1, ws1, result := m.client(id1)
+	if c1 == nil {
+		return dataMap("Result", "ClientSwap", result), nil
+	}
+	c2, ws2, result := m.client(id2)
+	if c2 == nil {
+		return dataMap("Result", "ClientSwap", result), nil
+	}
+
+	// Validate client workspaces
+	if ws1 != ws2 {
+		result = failure("InvalidClient", "clients %d and %d are not on the same workspace", id1, id2)
+		return dataMap("Result", "ClientSwap", result), nil
+	}
+
+	// Swap clients
+	ws1.ActiveLayout().GetManager().SwapClient(c1, c2)
+	m.Tracker.Tile(ws1)
+
+	return dataMap("Result", "ClientSwap", common.Map{"Success": true}), nil
+}
+
+func (m Methods) ClientMakeMaster(id int32) (string, *dbus.Error) {
+	c, ws, result := m.client(id)
+	if c == nil {
+		return dataMap("Result", "ClientMakeMaster", result), nil
+	}
+
+	// Make client master
+	ws.ActiveLayout().MakeMaster(c)
+	m.Tracker.Tile(ws)
+
+	return dataMap("Result", "ClientMakeMaster", common.Map{"Success": true}), nil
+}
+
+func (m Methods) ClientOrderSet(desktop int32, screen int32, ids []int32) (string, *dbus.Error) {
+	ws, result := m.workspace(desktop, screen)
+	if ws == nil {
+		return dataMap("Result", "ClientOrderSet", result), nil
+	}
+	if ws.TilingDisabled() {
+		result = failure("TilingDisabled", "tiling is disabled on workspace")
+		return dataMap("Result", "ClientOrderSet", result), nil
+	}
+	mg := ws.ActiveLayout().GetManager()
+
+	// Validate client ids against workspace clients
+	clients := mg.Clients(store.Stacked)
+	if len(ids) != len(clients) {
+		result = failure("InvalidClient", "expected %d client ids, got %d", len(clients), len(ids))
+		return dataMap("Result", "ClientOrderSet", result), nil
+	}
+	ordered := make([]*store.Client, len(ids))
+	for i, id := range ids {
+		c, ok := m.Tracker.Clients[xproto.Window(id)]
+		if !ok || m.Tracker.ClientWorkspace(c) != ws || mg.Index(mg.Masters, c) < 0 && mg.Index(mg.Slaves, c) < 0 {
+			result = failure("InvalidClient", "client %d is not tiled on workspace", id)
+			return dataMap("Result", "ClientOrderSet", result), nil
+		}
+		for _, co := range ordered[:i] {
+			if co == c {
+				result = failure("InvalidClient", "client %d is listed twice", id)
+				return dataMap("Result", "ClientOrderSet", result), nil
+			}
+		}
+		ordered[i] = c
+	}
+
+	// Swap clients into order
+	for i, c := range ordered {
+		if current := mg.Clients(store.Stacked)[i]; current != c {
+			mg.SwapClient(current, c)
+		}
+	}
+	m.Tracker.Tile(ws)
+
+	return dataMap("Result", "ClientOrderSet", common.Map{"Success": true}), nil
+}
+
+func (m Methods) TilingSet(desktop int32, screen int32, enabled bool) (string, *dbus.Error) {
+	ws, result := m.workspace(desktop, screen)
+	if ws == nil {
+		return dataMap("Result", "TilingSet", result), nil
+	}
+
+	// Enable or disable tiling
+	if enabled && ws.TilingDisabled() {
+		EnableTiling(m.Tracker, ws)
+	} else if !enabled && ws.TilingEnabled() {
+		DisableTiling(m.Tracker, ws)
+	}
+
+	return dataMap("Result", "TilingSet", common.Map{"Success": true}), nil
+}
+
+func (m Methods) TreeGet() (string, *dbus.Error) {
+
+	// Sort workspaces by desktop and screen
+	locations := maps.Keys(m.Tracker.Workspaces)
+	sort.Slice(locations, func(i, j int) bool {
+		if locations[i].Desktop == locations[j].Desktop {
+			return locations[i].Screen < locations[j].Screen
+		}
+		return locations[i].Desktop < locations[j].Desktop
+	})
+
+	// Collect workspace trees
+	workspaces := []TreeWorkspace{}
+	for _, location := range locations {
+		ws := m.Tracker.Workspaces[location]
+		mg := ws.ActiveLayout().GetManager()
+		workspaces = append(workspaces, TreeWorkspace{
+			Desktop:     location.Desktop,
+			Screen:      location.Screen,
+			Layout:      ws.ActiveLayout().GetName(),
+			Tiling:      ws.TilingEnabled(),
+			Proportions: ws.ActiveLayout().GetProportions(),
+			Masters:     treeClients(mg.Masters.Stacked),
+			Slaves:      treeClients(mg.Slaves.Stacked),
+		})
+	}
+
+	// Return result
+	result := common.Map{"Workspaces": workspaces}
+
+	return dataMap("Result", "TreeGet", result), nil
+}
+
+func createMethods(tr *desktop.Tracker) *Methods {
+	return &Methods{
+		Naming: map[string][]string{
+			"ActionExecute":    {"name", "desktop", "screen"},
+			"WindowActivate":   {"id"},
+			"WindowToPosition": {"id", "x", "y"},
+			"WindowToDesktop":  {"id", "desktop"},
+			"WindowToScreen":   {"id", "screen"},
+			"ScratchpadSet":    {"name"},
+			"ScratchpadToggle": {"name"},
+			"ProfileList":      {},
+			"DesktopSwitch":    {"desktop"},
+			"LayoutSet":        {"desktop", "screen", "name"},
+			"ProportionsSet":   {"desktop", "screen", "kind", "values"},
+			"MastersSet":       {"desktop", "screen", "count"},
+			"SlavesSet":        {"desktop", "screen", "count"},
+			"ClientSwap":       {"id1", "id2"},
+			"ClientMakeMaster": {"id"},
+			"ClientOrderSet":   {"desktop", "screen", "ids"},
+			"TilingSet":        {"desktop", "screen", "enabled"},
+			"TreeGet":          {},
+		},
+		Tracker: tr,
+	}
+}
+
+func createProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"Process":       structToMap(common.Process),
+		"Build":         structToMap(common.Build),
+		"Source":        structToMap(common.Source),
+		"Arguments":     structToMap(common.Args),
+		"Configuration": structToMap(common.Config),
+		"Workspaces":    common.Map{},
+		"Workplace":     common.Map{},
+		"Windows":       common.Map{},
+		"Clients":       common.Map{},
+		"Scratchpads":   common.Map{},
+		"Pointer":       common.Map{},
+		"Action":        common.Map{},
+		"Corner":        common.Map{},
+		"Disconnect":    common.Map{},
+	}
+}
+
+func (m Methods) workspace(desktop int32, screen int32) (*desktop.Workspace, common.Map) {
+
+	// Validate location against workplace
+	if desktop < 0 || uint(desktop) >= store.Workplace.DesktopCount {
+		return nil, failure("InvalidDesktop", "desktop %d is out of range [0, %d]", desktop, int(store.Workplace.DesktopCount)-1)
+	}
+	if screen < 0 || uint(screen) >= store.Workplace.ScreenCount {
+		return nil, failure("InvalidScreen", "screen %d is out of range [0, %d]", screen, int(store.Workplace.ScreenCount)-1)
+	}
+
+	// Obtain workspace at location
+	ws := m.Tracker.WorkspaceAt(uint(desktop), uint(screen))
+	if ws == nil {
+		return nil, failure("InvalidWorkspace", "workspace %d-%d does not exist", desktop, screen)
+	}
+
+	return ws, nil
+}
+
+func (m Methods) client(id int32) (*store.Client, *desktop.Workspace, common.Map) {
+
+	// Validate client against tracker
+	c, ok := m.Tracker.Clients[xproto.Window(id)]
+	if !ok {
+		return nil, nil, failure("InvalidClient", "client %d is not tracked", id)
+	}
+	ws := m.Tracker.ClientWorkspace(c)
+	if ws == nil || c.IsFloating() {
+		return nil, nil, failure("InvalidClient", "client %d is not tiled", id)
+	}
+	if ws.TilingDisabled() {
+		return nil, nil, failure("TilingDisabled", "tiling is disabled on workspace")
+	}
+
+	return c, ws, nil
+}
+
 func (m Methods) Introspection() []introspect.Method {
 	typ := reflect.TypeOf(m)
 	ims := make([]introspect.Method, 0, typ.NumMethod())
@@ -207,15 +600,24 @@ func BindDbus(tr *desktop.Tracker) {
 
 func event(ch chan string, tr *desktop.Tracker) {
 	for {
-		switch <-ch {
+		event := <-ch
+		switch event {
 		case "clients_change":
-			SetProperty("Clients", common.Map{"Values": maps.Values(tr.Clients)})
+			SetProperty("Clients", common.Map{"Values": clientValues(tr)})
 		case "workspaces_change":
 			SetProperty("Workspaces", common.Map{"Values": maps.Values(tr.Workspaces)})
 		case "workplace_change":
 			SetProperty("Workplace", *store.Workplace)
 		case "windows_change":
 			SetProperty("Windows", *store.Windows)
+		case "scratchpads_change":
+			scratchpads := []*store.Client{}
+			for _, c := range tr.Clients {
+				if c.IsScratchpad() {
+					scratchpads = append(scratchpads, c)
+				}
+			}
+			SetProperty("Scratchpads", common.Map{"Values": scratchpads})
 		case "corner_change":
 			for _, hc := range store.Workplace.Displays.Corners {
 				if !hc.Active {
@@ -230,6 +632,14 @@ func event(ch chan string, tr *desktop.Tracker) {
 				})
 			}
 		}
+
+		// Emit discrete signals
+		if common.IsInList(event, []string{"clients_change", "workspaces_change", "windows_change"}) {
+			emitSignals(tr)
+		}
+
+		// Execute callbacks
+		eventCallbacks(event)
 	}
 }
 
@@ -261,21 +671,7 @@ func export(tr *desktop.Tracker) {
 	}
 
 	// Export dbus properties
-	mapping := map[string]interface{}{
-		"Process":       structToMap(common.Process),
-		"Build":         structToMap(common.Build),
-		"Source":        structToMap(common.Source),
-		"Arguments":     structToMap(common.Args),
-		"Configuration": structToMap(common.Config),
-		"Workspaces":    common.Map{},
-		"Workplace":     common.Map{},
-		"Windows":       common.Map{},
-		"Clients":       common.Map{},
-		"Pointer":       common.Map{},
-		"Action":        common.Map{},
-		"Corner":        common.Map{},
-		"Disconnect":    common.Map{},
-	}
+	mapping := createProperties()
 	properties := map[string]*prop.Prop{}
 	for name, value := range mapping {
 		properties[name] = &prop.Prop{
@@ -291,17 +687,7 @@ func export(tr *desktop.Tracker) {
 	}
 
 	// Export dbus methods
-	methods = &Methods{
-		Naming: map[string][]string{
-			"ActionExecute":    {"name", "desktop", "screen"},
-			"WindowActivate":   {"id"},
-			"WindowToPosition": {"id", "x", "y"},
-			"WindowToDesktop":  {"id", "desktop"},
-			"WindowToScreen":   {"id", "screen"},
-			"DesktopSwitch":    {"desktop"},
-		},
-		Tracker: tr,
-	}
+	methods = createMethods(tr)
 	err = conn.Export(methods, opath, iface)
 	if err != nil {
 		log.Warn("Error exporting dbus methods: ", err)
@@ -318,6 +704,7 @@ func export(tr *desktop.Tracker) {
 				Name:       iface,
 				Methods:    methods.Introspection(),
 				Properties: props.Introspection(iface),
+				Signals:    signals,
 			},
 		},
 	})
@@ -327,6 +714,9 @@ func export(tr *desktop.Tracker) {
 		return
 	}
 
+	// Enable dbus signals
+	setSignalBus(conn)
+
 	select {}
 }
 
@@ -346,6 +736,7 @@ func Introspect() map[string][]string {
 	// Iterate node interfaces
 	methods := []string{}
 	properties := []string{}
+	signals := []string{}
 	for _, item := range node.Interfaces {
 		if item.Name != iface {
 			continue
@@ -353,35 +744,52 @@ func Introspect() map[string][]string {
 
 		// Get dbus methods
 		for _, method := range item.Methods {
-			description := method.Name
-			for _, arg := range method.Args {
-				if arg.Direction != "in" {
-					continue
-				}
-				switch arg.Type {
-				case "s":
-					description += fmt.Sprintf(" str:%s", arg.Name)
-				case "i":
-					description += fmt.Sprintf(" int:%s", arg.Name)
-				}
-			}
-			methods = append(methods, description)
+			methods = append(methods, methodDescription(method))
 		}
 
 		// Get dbus properties
 		for _, property := range item.Properties {
 			properties = append(properties, property.Name)
 		}
+
+		// Get dbus signals
+		for _, signal := range item.Signals {
+			signals = append(signals, signal.Name)
+		}
 	}
 	sort.Strings(methods)
 	sort.Strings(properties)
+	sort.Strings(signals)
 
 	return map[string][]string{
 		"Methods":    methods,
 		"Properties": properties,
+		"Signals":    signals,
 	}
 }
 
+func methodDescription(method introspect.Method) string {
+	description := method.Name
+	for _, arg := range method.Args {
+		if arg.Direction != "in" {
+			continue
+		}
+		switch arg.Type {
+		case "s":
+			description += fmt.Sprintf(" str:%s", arg.Name)
+		case "i":
+			description += fmt.Sprintf(" int:%s", arg.Name)
+		case "b":
+			description += fmt.Sprintf(" bool:%s", arg.Name)
+		case "ai":
+			description += fmt.Sprintf(" ints:%s", arg.Name)
+		case "ad":
+			description += fmt.Sprintf(" floats:%s", arg.Name)
+		}
+	}
+	return description
+}
+
 func Method(name string, args []string) {
 	conn, err := connect()
 	if err != nil {
@@ -392,12 +800,7 @@ func Method(name string, args []string) {
 	// Convert arguments
 	variants := make([]interface{}, len(args))
 	for i, value := range args {
-		integer, err := strconv.Atoi(value)
-		if err == nil {
-			variants[i] = dbus.MakeVariant(integer)
-		} else {
-			variants[i] = dbus.MakeVariant(value)
-		}
+		variants[i] = argumentToVariant(value)
 	}
 
 	// Call dbus method
@@ -441,9 +844,10 @@ func Listen(args []string) {
 	}
 	defer conn.Close()
 
-	// Monitor property changes and method calls
+	// Monitor property changes, signals and method calls
 	call := conn.BusObject().Call("org.freedesktop.DBus.Monitoring.BecomeMonitor", 0, []string{
 		fmt.Sprintf("type='signal',interface='org.freedesktop.DBus.Properties',member='PropertiesChanged',path='%s'", opath),
+		fmt.Sprintf("type='signal',interface='%s',path='%s'", iface, opath),
 		fmt.Sprintf("type='method_call',interface='%s',path='%s'", iface, opath),
 	}, uint(0))
 	if call.Err != nil {
@@ -469,6 +873,14 @@ func Listen(args []string) {
 				}
 			}
 		default:
+			if msg.Type == dbus.TypeSignal {
+				typ := "Signal"
+				filter := fmt.Sprintf("%s:%s", typ, method)
+				if len(args) == 0 || common.IsInList(filter, args) {
+					print(typ, method, signalToMap(method, msg.Body))
+				}
+				continue
+			}
 			typ := "Method"
 			filter := fmt.Sprintf("%s:%s", typ, method)
 			if len(args) == 0 || common.IsInList(filter, args) {
@@ -495,18 +907,82 @@ func GetProperty(name string) common.Map {
 }
 
 func SetProperty(name string, obj interface{}) {
+	value := structToMap(obj)
+
+	// Publish to ipc listeners
+	publish("Property", name, value)
+
 	if props == nil {
 		return
 	}
-	variant := dbus.MakeVariant(structToMap(obj))
+	variant := dbus.MakeVariant(value)
 	props.SetMust(iface, name, variant)
 }
 
+func argumentToVariant(value string) dbus.Variant {
+
+	// Convert comma separated lists
+	if strings.Contains(value, ",") {
+		items := strings.Split(value, ",")
+		integers := make([]int32, len(items))
+		floats := make([]float64, len(items))
+		for j, item := range items {
+			integer, err := strconv.Atoi(strings.TrimSpace(item))
+			if err != nil {
+				integers = nil
+			} else if integers != nil {
+				integers[j] = int32(integer)
+			}
+			float, err := strconv.ParseFloat(strings.TrimSpace(item), 64)
+			if err != nil {
+				return dbus.MakeVariant(value)
+			}
+			floats[j] = float
+		}
+		if integers != nil {
+			return dbus.MakeVariant(integers)
+		}
+		return dbus.MakeVariant(floats)
+	}
+
+	// Convert single values
+	if integer, err := strconv.Atoi(value); err == nil {
+		return dbus.MakeVariant(integer)
+	}
+	if boolean, err := strconv.ParseBool(value); err == nil {
+		return dbus.MakeVariant(boolean)
+	}
+
+	return dbus.MakeVariant(value)
+}
+
+func signalToMap(name string, body []interface{}) common.Map {
+	value := common.Map{}
+	for i, arg := range signalArgs(name) {
+		if i < len(body) {
+			value[arg.Name] = body[i]
+		}
+	}
+	return value
+}
+
 func variantToMap(variant dbus.Variant) (value common.Map) {
 	variant.Store(&value)
 	return value
 }
 
+func clientValues(tr *desktop.Tracker) []interface{} {
+	values := []interface{}{}
+	for _, c := range tr.Clients {
+		values = append(values, struct {
+			*store.Client
+			Rule       *common.Rule // Matching window rule from config
+			Scratchpad string       // Client scratchpad name
+		}{c, c.Rule, c.Scratchpad})
+	}
+	return values
+}
+
 func structToMap(obj interface{}) (value common.Map) {
 	data, err := json.Marshal(obj)
 	if err != nil {
@@ -529,6 +1005,17 @@ func bodyToString(obj interface{}) string {
 	return r.Replace(fmt.Sprint(obj))
 }
 
+func failure(code string, format string, args ...interface{}) common.Map {
+	return common.Map{"Success": false, "Error": common.Map{"Code": code, "Message": fmt.Sprintf(format, args...)}}
+}
+
+func outcome(success bool, code string, format string, args ...interface{}) common.Map {
+	if !success {
+		return failure(code, format, args...)
+	}
+	return common.Map{"Success": true}
+}
+
 func dataMap(typ string, name string, data common.Map) string {
 	time := time.Now().UnixMilli()
 	process := common.Process.Id
@@ -0,0 +1,219 @@
+package input
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	bus      *dbus.Conn          // Dbus connection for signals
+	busMutex sync.Mutex          // Dbus connection lock
+	snapshot *signalSnapshot     // Last seen state for signals
+	signals  []introspect.Signal = []introspect.Signal{
+		createSignal("ClientAdded", "id:i", "class:s", "desktop:i", "screen:i"),
+		createSignal("ClientRemoved", "id:i", "class:s"),
+		createSignal("ClientMoved", "id:i", "from_desktop:i", "from_screen:i", "to_desktop:i", "to_screen:i"),
+		createSignal("LayoutChanged", "desktop:i", "screen:i", "name:s"),
+		createSignal("TilingToggled", "desktop:i", "screen:i", "enabled:b"),
+		createSignal("FocusChanged", "id:i", "class:s"),
+		createSignal("ProportionsChanged", "desktop:i", "screen:i", "kind:s", "key:i", "values:ad"),
+	} // Dbus signals and arguments
+)
+
+type signalSnapshot struct {
+	Clients     map[xproto.Window]signalClient            // Tracked clients
+	Workspaces  map[store.Location]signalWorkspace        // Workspace states
+	Proportions map[string]map[signalProportion][]float64 // Proportions per workspace layout
+	Active      xproto.Window                             // Active window id
+}
+
+type signalClient struct {
+	Class    string         // Client window application name
+	Location store.Location // Client desktop and screen location
+}
+
+type signalProportion struct {
+	Kind string // Proportions kind name
+	Key  int    // Proportions count or index
+}
+
+type signalWorkspace struct {
+	Layout string // Active layout name
+	Tiling bool   // Tiling is enabled
+}
+
+func createSignal(name string, args ...string) introspect.Signal {
+	s := introspect.Signal{Name: name, Args: make([]introspect.Arg, len(args))}
+	for i, arg := range args {
+		aname, atype, _ := strings.Cut(arg, ":")
+		s.Args[i] = introspect.Arg{Name: aname, Type: atype, Direction: "out"}
+	}
+	return s
+}
+
+func signalArgs(name string) []introspect.Arg {
+	for _, s := range signals {
+		if s.Name == name {
+			return s.Args
+		}
+	}
+	return []introspect.Arg{}
+}
+
+func emit(name string, args ...interface{}) {
+
+	// Check signal definition
+	sargs := signalArgs(name)
+	if len(sargs) != len(args) {
+		log.Warn("Error emitting dbus signal ", name, ": invalid arguments")
+		return
+	}
+	for i, arg := range args {
+		if dbus.SignatureOf(arg).String() != sargs[i].Type {
+			log.Warn("Error emitting dbus signal ", name, ": invalid argument ", sargs[i].Name)
+			return
+		}
+	}
+
+	// Publish to ipc listeners
+	publish("Signal", name, signalToMap(name, args))
+
+	// Emit dbus signal
+	conn := signalBus()
+	if conn == nil {
+		return
+	}
+	err := conn.Emit(opath, fmt.Sprintf("%s.%s", iface, name), args...)
+	if err != nil {
+		log.Warn("Error emitting dbus signal ", name, ": ", err)
+	}
+}
+
+func setSignalBus(conn *dbus.Conn) {
+	busMutex.Lock()
+	defer busMutex.Unlock()
+
+	bus = conn
+}
+
+func signalBus() *dbus.Conn {
+	busMutex.Lock()
+	defer busMutex.Unlock()
+
+	return bus
+}
+
+func emitSignals(tr *desktop.Tracker) {
+	latest := createSnapshot(tr)
+	previous := snapshot
+	snapshot = latest
+
+	// Skip initial state
+	if previous == nil {
+		return
+	}
+
+	// Emit client signals
+	for id, c := range latest.Clients {
+		p, ok := previous.Clients[id]
+		if !ok {
+			emit("ClientAdded", int32(id), c.Class, int32(c.Location.Desktop), int32(c.Location.Screen))
+		} else if p.Location != c.Location {
+			emit("ClientMoved", int32(id), int32(p.Location.Desktop), int32(p.Location.Screen), int32(c.Location.Desktop), int32(c.Location.Screen))
+		}
+	}
+	for id, p := range previous.Clients {
+		if _, ok := latest.Clients[id]; !ok {
+			emit("ClientRemoved", int32(id), p.Class)
+		}
+	}
+
+	// Emit workspace signals
+	for location, ws := range latest.Workspaces {
+		p, ok := previous.Workspaces[location]
+		if !ok {
+			continue
+		}
+		if p.Layout != ws.Layout {
+			emit("LayoutChanged", int32(location.Desktop), int32(location.Screen), ws.Layout)
+		}
+		if p.Tiling != ws.Tiling {
+			emit("TilingToggled", int32(location.Desktop), int32(location.Screen), ws.Tiling)
+		}
+
+		// Emit proportion signals of active layout
+		key := proportionsKey(location, ws.Layout)
+		for kind, values := range latest.Proportions[key] {
+			pvalues, ok := previous.Proportions[key][kind]
+			if !ok || reflect.DeepEqual(pvalues, values) {
+				continue
+			}
+			emit("ProportionsChanged", int32(location.Desktop), int32(location.Screen), kind.Kind, int32(kind.Key), values)
+		}
+	}
+
+	// Emit focus signals
+	if previous.Active != latest.Active {
+		if c, ok := latest.Clients[latest.Active]; ok {
+			emit("FocusChanged", int32(latest.Active), c.Class)
+		} else {
+			emit("FocusChanged", int32(latest.Active), "")
+		}
+	}
+}
+
+func createSnapshot(tr *desktop.Tracker) *signalSnapshot {
+	s := &signalSnapshot{
+		Clients:     make(map[xproto.Window]signalClient),
+		Workspaces:  make(map[store.Location]signalWorkspace),
+		Proportions: make(map[string]map[signalProportion][]float64),
+		Active:      store.Windows.Active.Id,
+	}
+
+	// Copy client states
+	for id, c := range tr.Clients {
+		s.Clients[id] = signalClient{Class: c.Latest.Class, Location: c.Latest.Location}
+	}
+
+	// Copy workspace states
+	for location, ws := range tr.Workspaces {
+		s.Workspaces[location] = signalWorkspace{Layout: ws.ActiveLayout().GetName(), Tiling: ws.TilingEnabled()}
+
+		// Copy proportions of each layout
+		for _, l := range ws.Layouts {
+			p := l.GetManager().Proportions
+			values := make(map[signalProportion][]float64)
+			for kind, proportions := range map[string]map[int][]float64{
+				"master_slave":  p.MasterSlave,
+				"master_master": p.MasterMaster,
+				"slave_slave":   p.SlaveSlave,
+				"splits":        p.Splits,
+				"columns":       p.Columns,
+				"rows":          p.Rows,
+			} {
+				for key, value := range proportions {
+					values[signalProportion{Kind: kind, Key: key}] = append([]float64{}, value...)
+				}
+			}
+			s.Proportions[proportionsKey(location, l.GetName())] = values
+		}
+	}
+
+	return s
+}
+
+func proportionsKey(location store.Location, layout string) string {
+	return fmt.Sprintf("%d-%d-%s", location.Desktop, location.Screen, layout)
+}
@@ -0,0 +1,384 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+
+	"encoding/json"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ipcMethods     *Methods                               // Ipc methods
+	ipcValues      map[string]common.Map                  // Ipc property values
+	ipcListeners   map[chan string][]string               // Ipc listeners and filters
+	ipcMutex       sync.Mutex                             // Ipc values and listeners lock
+	ipcBufferSize  int                      = 1024 * 1024 // Ipc maximum line size
+	ipcChannelSize int                      = 64          // Ipc listener queue size
+)
+
+type IpcRequest struct {
+	Method     string        `json:",omitempty"` // Method name to call
+	Args       []interface{} `json:",omitempty"` // Method arguments
+	Property   string        `json:",omitempty"` // Property name to read
+	Listen     bool          `json:",omitempty"` // Subscribe to event stream
+	Filter     []string      `json:",omitempty"` // Event stream filters
+	Introspect bool          `json:",omitempty"` // Describe available interfaces
+}
+
+func BindIpc(tr *desktop.Tracker) {
+	if common.HasFlag("disable-ipc-interface") {
+		return
+	}
+
+	// Init methods and property values
+	ipcMutex.Lock()
+	ipcMethods = createMethods(tr)
+	ipcValues = make(map[string]common.Map)
+	ipcListeners = make(map[chan string][]string)
+	for name, value := range createProperties() {
+		ipcValues[name] = value.(common.Map)
+	}
+	ipcMutex.Unlock()
+
+	// Serve socket connections
+	go serve()
+}
+
+func serve() {
+	path := common.SocketFilePath()
+
+	// Remove stale socket file
+	os.Remove(path)
+
+	// Listen on unix socket
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		log.Warn("Error initializing ipc server: ", err)
+		return
+	}
+	os.Chmod(path, 0600)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Warn("Error accepting ipc connection: ", err)
+			continue
+		}
+		go handle(conn)
+	}
+}
+
+func handle(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), ipcBufferSize)
+
+	for scanner.Scan() {
+		var request IpcRequest
+		err := json.Unmarshal(scanner.Bytes(), &request)
+		if err != nil {
+			reply(conn, dataMap("Error", "Request", common.Map{"Message": fmt.Sprintf("Invalid request: %s", err)}))
+			continue
+		}
+
+		// Handle request
+		switch {
+		case request.Listen:
+			stream(conn, request.Filter)
+			return
+		case request.Introspect:
+			reply(conn, mapToString(introspection()))
+		case len(request.Method) > 0:
+			reply(conn, call(request.Method, request.Args))
+		case len(request.Property) > 0:
+			reply(conn, property(request.Property))
+		default:
+			reply(conn, dataMap("Error", "Request", common.Map{"Message": "Missing request type"}))
+		}
+	}
+}
+
+func reply(conn net.Conn, line string) error {
+	_, err := fmt.Fprintln(conn, line)
+	return err
+}
+
+func stream(conn net.Conn, filter []string) {
+	ch := make(chan string, ipcChannelSize)
+
+	// Register listener
+	ipcMutex.Lock()
+	ipcListeners[ch] = filter
+	ipcMutex.Unlock()
+
+	// Unregister listener
+	defer func() {
+		ipcMutex.Lock()
+		delete(ipcListeners, ch)
+		ipcMutex.Unlock()
+	}()
+
+	// Detect closed connections
+	closed := make(chan struct{})
+	go func() {
+		bufio.NewReader(conn).ReadByte()
+		close(closed)
+	}()
+
+	// Forward events
+	for {
+		select {
+		case line := <-ch:
+			if reply(conn, line) != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+func publish(typ string, name string, data common.Map) {
+	ipcMutex.Lock()
+	defer ipcMutex.Unlock()
+
+	// Store property value
+	if typ == "Property" && ipcValues != nil {
+		ipcValues[name] = data
+	}
+
+	// Notify matching listeners
+	line := dataMap(typ, name, data)
+	filter := fmt.Sprintf("%s:%s", typ, name)
+	for ch, args := range ipcListeners {
+		if len(args) > 0 && !common.IsInList(filter, args) {
+			continue
+		}
+		select {
+		case ch <- line:
+		default:
+			log.Warn("Error publishing ipc event ", filter, ": listener queue full")
+		}
+	}
+}
+
+func call(name string, args []interface{}) string {
+	if _, ok := ipcMethods.Naming[name]; !ok {
+		return dataMap("Error", "Method", common.Map{"Message": fmt.Sprintf("Unknown method %s", name)})
+	}
+
+	// Check method arguments
+	method := reflect.ValueOf(*ipcMethods).MethodByName(name)
+	mt := method.Type()
+	if mt.NumIn() != len(args) {
+		return dataMap("Error", "Method", common.Map{"Message": fmt.Sprintf("Method %s expects %d arguments", name, mt.NumIn())})
+	}
+
+	// Convert method arguments
+	values := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		value, err := argumentToValue(arg, mt.In(i))
+		if err != nil {
+			return dataMap("Error", "Method", common.Map{"Message": fmt.Sprintf("Invalid argument %s: %s", ipcMethods.Naming[name][i], err)})
+		}
+		values[i] = value
+	}
+
+	// Publish to ipc listeners
+	publish("Method", name, common.Map{"Body": bodyToString(args)})
+
+	// Call method
+	out := method.Call(values)
+	if err, ok := out[1].Interface().(*dbus.Error); ok && err != nil {
+		return dataMap("Error", "Method", common.Map{"Message": err.Error()})
+	}
+
+	return out[0].String()
+}
+
+func property(name string) string {
+	ipcMutex.Lock()
+	defer ipcMutex.Unlock()
+
+	value, ok := ipcValues[name]
+	if !ok {
+		return dataMap("Error", "Property", common.Map{"Message": fmt.Sprintf("Unknown property %s", name)})
+	}
+
+	return dataMap("Property", name, value)
+}
+
+func introspection() map[string][]string {
+	methods := []string{}
+	for _, method := range ipcMethods.Introspection() {
+		methods = append(methods, methodDescription(method))
+	}
+
+	ipcMutex.Lock()
+	properties := []string{}
+	for name := range ipcValues {
+		properties = append(properties, name)
+	}
+	ipcMutex.Unlock()
+
+	names := []string{}
+	for _, signal := range signals {
+		names = append(names, signal.Name)
+	}
+
+	sort.Strings(methods)
+	sort.Strings(properties)
+	sort.Strings(names)
+
+	return map[string][]string{
+		"Methods":    methods,
+		"Properties": properties,
+		"Signals":    names,
+	}
+}
+
+func IpcIntrospect() map[string][]string {
+	conn, err := net.Dial("unix", common.SocketFilePath())
+	if err != nil {
+		return map[string][]string{}
+	}
+	defer conn.Close()
+
+	// Request introspection
+	if request(conn, IpcRequest{Introspect: true}) != nil {
+		return map[string][]string{}
+	}
+
+	// Read reply
+	value := map[string][]string{}
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil || json.Unmarshal(line, &value) != nil {
+		return map[string][]string{}
+	}
+
+	return value
+}
+
+func IpcMethod(name string, args []string) {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg
+	}
+	ipcRequest(IpcRequest{Method: name, Args: values}, false)
+}
+
+func IpcProperty(name string) {
+	ipcRequest(IpcRequest{Property: name}, false)
+}
+
+func IpcListen(args []string) {
+	ipcRequest(IpcRequest{Listen: true, Filter: args}, true)
+}
+
+func ipcRequest(req IpcRequest, listen bool) {
+	conn, err := net.Dial("unix", common.SocketFilePath())
+	if err != nil {
+		fatal("Error connecting to ipc server", err)
+	}
+	defer conn.Close()
+
+	// Send request
+	err = request(conn, req)
+	if err != nil {
+		fatal("Error sending ipc request", err)
+	}
+
+	// Print replies
+	reader := bufio.NewReaderSize(conn, ipcBufferSize)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if listen {
+				fatal("Error reading ipc reply", err)
+			}
+			return
+		}
+		fmt.Print(line)
+		if !listen {
+			return
+		}
+	}
+}
+
+func request(conn net.Conn, req IpcRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(conn, "%s\n", data)
+	return err
+}
+
+func argumentToValue(arg interface{}, typ reflect.Type) (reflect.Value, error) {
+
+	// Convert command line strings
+	if text, ok := arg.(string); ok && typ.Kind() != reflect.String {
+		arg = argumentToVariant(text).Value()
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		if text, ok := arg.(string); ok {
+			return reflect.ValueOf(text), nil
+		}
+	case reflect.Bool:
+		if boolean, ok := arg.(bool); ok {
+			return reflect.ValueOf(boolean), nil
+		}
+	case reflect.Int32:
+		if number, ok := argumentToFloat(arg); ok && number == math.Trunc(number) {
+			return reflect.ValueOf(int32(number)), nil
+		}
+	case reflect.Float64:
+		if number, ok := argumentToFloat(arg); ok {
+			return reflect.ValueOf(number), nil
+		}
+	case reflect.Slice:
+		items := reflect.ValueOf(arg)
+		if items.Kind() != reflect.Slice {
+			items = reflect.ValueOf([]interface{}{arg})
+		}
+		values := reflect.MakeSlice(typ, items.Len(), items.Len())
+		for i := 0; i < items.Len(); i++ {
+			value, err := argumentToValue(items.Index(i).Interface(), typ.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			values.Index(i).Set(value)
+		}
+		return values, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("expected %s, got %v", typ, arg)
+}
+
+func argumentToFloat(arg interface{}) (float64, bool) {
+	switch number := arg.(type) {
+	case float64:
+		return number, true
+	case int:
+		return float64(number), true
+	case int32:
+		return float64(number), true
+	}
+	return 0, false
+}
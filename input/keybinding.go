@@ -3,6 +3,8 @@ package input
 import (
 	"strings"
 
+	"github.com/jezek/xgb/xproto"
+
 	"github.com/jezek/xgbutil"
 	"github.com/jezek/xgbutil/keybind"
 	"github.com/jezek/xgbutil/xevent"
@@ -10,10 +12,29 @@ import (
 	"github.com/leukipp/cortile/v2/common"
 	"github.com/leukipp/cortile/v2/desktop"
 	"github.com/leukipp/cortile/v2/store"
+	"github.com/leukipp/cortile/v2/ui"
 
 	log "github.com/sirupsen/logrus"
 )
 
+var (
+	mode  string                   // Active key mode name
+	chord []string                 // Typed key sequence of chord
+	chain []*binding               // Chord key bindings
+	hints map[string]*store.Client // Labeled clients of hints mode
+	press xproto.KeyPressEvent     // Last key press handled by a binding
+)
+
+var (
+	labels []string = strings.Split("asdfghjklqwertyuiopzxcvbnm", "") // Letters used for hints
+)
+
+type binding struct {
+	Keys   []string // Key sequence of binding
+	Action string   // Action of binding
+	Mod    string   // Modifier of binding
+}
+
 func BindKeys(tr *desktop.Tracker) {
 	keybind.Initialize(store.X)
 
@@ -22,7 +43,7 @@ func BindKeys(tr *desktop.Tracker) {
 
 	// Map actions and modifiers
 	for c, ck := range common.Config.Keys {
-		if len(ck) == 0 {
+		if len(strings.TrimSpace(ck)) == 0 {
 			continue
 		}
 		if !strings.HasPrefix(c, "mod_") {
@@ -32,23 +53,133 @@ func BindKeys(tr *desktop.Tracker) {
 		}
 	}
 
-	// Bind keyboard shortcuts
+	// Bind keyboard shortcuts and chords
 	for a, ak := range actions {
 		for m, mk := range mods {
-			if len(mk) == 0 {
-				bind(ak, a, m, tr)
+			keys := strings.Fields(ak)
+			if len(mk) > 0 {
+				keys[0] = mk + "-" + keys[0]
+			}
+			if len(keys) == 1 {
+				bind(keys[0], a, m, tr)
 			} else {
-				bind(mk+"-"+ak, a, m, tr)
+				bindChord(&binding{Keys: keys, Action: a, Mod: m}, tr)
 			}
 		}
 	}
 
+	// Bind mode shortcuts
+	for name, keys := range common.Config.Modes {
+		for a, ak := range keys {
+			if len(strings.TrimSpace(ak)) == 0 {
+				continue
+			}
+			bindMode(ak, a, name, tr)
+		}
+	}
+
+	// Bind chord and mode exit
+	bindExit(tr)
+
 	// Bind action channel
 	go action(tr.Channels.Action, tr)
 }
 
+func EnterMode(tr *desktop.Tracker, name string) bool {
+	if _, ok := common.Config.Modes[name]; !ok {
+		log.Warn("Invalid key mode ", name)
+		return false
+	}
+	if mode == name {
+		return true
+	}
+
+	// Grab keyboard until mode exits
+	if err := keybind.GrabKeyboard(store.X, store.X.RootWin()); err != nil {
+		log.Warn("Error entering key mode ", name, ": ", err)
+		return false
+	}
+	log.Info("Enter key mode ", name)
+
+	setMode(tr, name)
+
+	return true
+}
+
+func ExitMode(tr *desktop.Tracker) bool {
+	if len(mode) == 0 {
+		return false
+	}
+	log.Info("Exit key mode ", mode)
+
+	// Release keyboard if no chord is typed
+	if len(chord) == 0 {
+		keybind.UngrabKeyboard(store.X)
+	}
+
+	setMode(tr, "")
+
+	return true
+}
+
+func EnterHints(tr *desktop.Tracker) bool {
+	if len(chord) > 0 || len(hints) > 0 {
+		return false
+	}
+
+	// Label visible clients on all screens of current desktop
+	labeled := make(map[string]*store.Client)
+	for screen := uint(0); screen < store.Workplace.ScreenCount; screen++ {
+		ws := tr.WorkspaceAt(store.Workplace.CurrentDesktop, screen)
+		if ws == nil {
+			continue
+		}
+		for _, c := range ws.VisibleClients() {
+			if c == nil || len(labeled) >= len(labels) {
+				continue
+			}
+			labeled[labels[len(labeled)]] = c
+		}
+	}
+	if len(labeled) == 0 {
+		return false
+	}
+
+	// Grab keyboard until a hint is typed
+	if len(mode) == 0 {
+		if err := keybind.GrabKeyboard(store.X, store.X.RootWin()); err != nil {
+			log.Warn("Error entering hints: ", err)
+			return false
+		}
+	}
+	log.Info("Enter hints [", len(labeled), " clients]")
+
+	hints = labeled
+	ui.ShowHints(hints)
+
+	return true
+}
+
+func ExitHints(tr *desktop.Tracker) bool {
+	if len(hints) == 0 {
+		return false
+	}
+	log.Info("Exit hints")
+
+	// Release keyboard if no mode is active
+	if len(mode) == 0 {
+		keybind.UngrabKeyboard(store.X)
+	}
+
+	hints = nil
+	ui.HideHints()
+
+	return true
+}
+
 func bind(key string, action string, mod string, tr *desktop.Tracker) {
 	err := keybind.KeyPressFun(func(X *xgbutil.XUtil, ev xevent.KeyPressEvent) {
+		setPress(ev)
 		ExecuteActions(action, tr, mod)
 	}).Connect(store.X, store.X.RootWin(), key, true)
 
@@ -57,6 +188,187 @@ func bind(key string, action string, mod string, tr *desktop.Tracker) {
 	}
 }
 
+func bindChord(b *binding, tr *desktop.Tracker) {
+	chain = append(chain, b)
+
+	// Grab first key only, subsequent keys are received during keyboard grab
+	for i, key := range b.Keys {
+		step := i
+		err := keybind.KeyPressFun(func(X *xgbutil.XUtil, ev xevent.KeyPressEvent) {
+			if !isTyped(b.Keys[:step]) {
+				return
+			}
+			setPress(ev)
+
+			// Execute action on last key
+			if step == len(b.Keys)-1 {
+				resetChord()
+				ExecuteActions(b.Action, tr, b.Mod)
+				return
+			}
+
+			// Wait for next key
+			if step == 0 && len(mode) == 0 {
+				if err := keybind.GrabKeyboard(store.X, store.X.RootWin()); err != nil {
+					log.Warn("Error on chord ", b.Action, ": ", err)
+					return
+				}
+			}
+			chord = b.Keys[:step+1]
+		}).Connect(store.X, store.X.RootWin(), key, step == 0)
+
+		if err != nil {
+			log.Warn("Error on chord ", b.Action, ": ", err)
+			return
+		}
+	}
+}
+
+func bindMode(key string, action string, name string, tr *desktop.Tracker) {
+	err := keybind.KeyPressFun(func(X *xgbutil.XUtil, ev xevent.KeyPressEvent) {
+		if mode != name || len(chord) > 0 || len(hints) > 0 {
+			return
+		}
+		setPress(ev)
+		ExecuteActions(action, tr, "current")
+	}).Connect(store.X, store.X.RootWin(), key, false)
+
+	if err != nil {
+		log.Warn("Error on mode ", name, " action ", action, ": ", err)
+	}
+}
+
+func bindExit(tr *desktop.Tracker) {
+	keybind.KeyPressFun(func(X *xgbutil.XUtil, ev xevent.KeyPressEvent) {
+		setPress(ev)
+		if len(chord) > 0 {
+			resetChord()
+			return
+		}
+		if ExitHints(tr) {
+			return
+		}
+		ExitMode(tr)
+	}).Connect(store.X, store.X.RootWin(), "Escape", false)
+
+	// Cancel chord on unknown keys, skipping keys handled by bindings
+	xevent.KeyPressFun(func(X *xgbutil.XUtil, ev xevent.KeyPressEvent) {
+		if len(chord) == 0 || isPressed(ev) || keybind.ModGet(store.X, ev.Detail) != 0 {
+			return
+		}
+		for _, b := range chain {
+			if len(b.Keys) > len(chord) && isTyped(b.Keys[:len(chord)]) && isKey(b.Keys[len(chord)], ev) {
+				return
+			}
+		}
+		resetChord()
+	}).Connect(store.X, store.X.RootWin())
+
+	// Focus or swap clients on hint keys, skipping the key press that entered hints
+	xevent.KeyPressFun(func(X *xgbutil.XUtil, ev xevent.KeyPressEvent) {
+		if len(hints) == 0 || isPressed(ev) || keybind.ModGet(store.X, ev.Detail) != 0 {
+			return
+		}
+		key := keybind.LookupString(store.X, ev.State, ev.Detail)
+		if key == "Escape" {
+			return
+		}
+		selectHint(tr, strings.ToLower(key), ev.State&xproto.ModMaskShift != 0)
+	}).Connect(store.X, store.X.RootWin())
+}
+
+func selectHint(tr *desktop.Tracker, key string, swap bool) {
+	c, ok := hints[key]
+	ExitHints(tr)
+	if !ok {
+		return
+	}
+
+	// Focus hinted client
+	if !swap {
+		store.ActiveWindowSet(store.X, c.Window)
+		return
+	}
+
+	// Swap hinted client with active client
+	active := tr.ActiveClient()
+	if active == nil || active == c {
+		return
+	}
+	ws := tr.ClientWorkspace(c)
+	if ws == nil || ws.TilingDisabled() {
+		return
+	}
+
+	// Move clients across screens of current desktop
+	if ws != tr.ClientWorkspace(active) {
+		tr.SwapScreens(active, c)
+		return
+	}
+	ws.ActiveLayout().GetManager().SwapClient(active, c)
+	tr.Tile(ws)
+}
+
+func resetChord() {
+	if len(chord) == 0 {
+		return
+	}
+	chord = nil
+
+	// Release keyboard if no mode is active
+	if len(mode) == 0 {
+		keybind.UngrabKeyboard(store.X)
+	}
+}
+
+func setMode(tr *desktop.Tracker, name string) {
+	mode = name
+
+	// Update mode indicators
+	ui.ShowMode(tr.ActiveWorkspace(), name)
+	onModeChange(name)
+}
+
+func setPress(ev xevent.KeyPressEvent) {
+	press = *ev.KeyPressEvent
+}
+
+func isPressed(ev xevent.KeyPressEvent) bool {
+	return ev.Time == press.Time && ev.Detail == press.Detail
+}
+
+func isTyped(keys []string) bool {
+	if len(keys) != len(chord) {
+		return false
+	}
+	for i, key := range keys {
+		if key != chord[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isKey(key string, ev xevent.KeyPressEvent) bool {
+	mods, kcs, err := keybind.ParseString(store.X, key)
+	if err != nil {
+		return false
+	}
+
+	// Compare modifiers and keycodes
+	m, kc := keybind.DeduceKeyInfo(ev.State, ev.Detail)
+	if m != mods {
+		return false
+	}
+	for _, k := range kcs {
+		if k == kc {
+			return true
+		}
+	}
+
+	return false
+}
+
 func action(ch chan string, tr *desktop.Tracker) {
 	for {
 		ExecuteAction(<-ch, tr, tr.ActiveWorkspace())
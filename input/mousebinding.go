@@ -3,6 +3,12 @@ package input
 import (
 	"time"
 
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/jezek/xgbutil"
+	"github.com/jezek/xgbutil/mousebind"
+	"github.com/jezek/xgbutil/xevent"
+
 	"github.com/leukipp/cortile/v2/common"
 	"github.com/leukipp/cortile/v2/desktop"
 	"github.com/leukipp/cortile/v2/store"
@@ -15,9 +21,25 @@ var (
 	workspace *desktop.Workspace // Stores previous workspace (for comparison only)
 	pointer   *store.XPointer    // Stores previous pointer (for comparison only)
 	hover     *time.Timer        // Timer to delay hover events
+	edges     *store.Directions  // Stores edges of resized client (during drag only)
+	resized   time.Time          // Stores time of last resize (for throttling only)
 )
 
 func BindMouse(tr *desktop.Tracker) {
+	mousebind.Initialize(store.X)
+
+	// Bind buttons on tracked clients
+	desktop.OnTrack(func(c *store.Client) {
+		bindButtons(c, tr)
+	})
+
+	// Attach tracker events
+	OnEvent(func(event string) {
+		if event == "snap_change" {
+			ui.ShowSnap(tr)
+		}
+	})
+
 	poll(100, func() {
 		store.PointerUpdate(store.X)
 
@@ -38,13 +60,138 @@ func BindMouse(tr *desktop.Tracker) {
 	})
 }
 
+func bindButtons(c *store.Client, tr *desktop.Tracker) {
+	for a, ab := range common.Config.Mouse {
+		if len(ab) == 0 {
+			continue
+		}
+		action := a
+
+		var err error
+		switch action {
+		case "window_move":
+			err = bindDrag(c, ab, tr, nil, func(ws *desktop.Workspace, p common.Point) {
+				moveClient(c, ws, p, tr)
+			})
+		case "window_resize":
+			err = bindDrag(c, ab, tr, func(ws *desktop.Workspace, p common.Point) {
+				resizeClient(c, ws, p, tr, false)
+			}, func(ws *desktop.Workspace, p common.Point) {
+				resizeClient(c, ws, p, tr, true)
+			})
+		default:
+			err = mousebind.ButtonPressFun(func(X *xgbutil.XUtil, ev xevent.ButtonPressEvent) {
+				ExecuteAction(action, tr, tr.ClientWorkspace(c))
+			}).Connect(store.X, c.Window.Id, ab, false, true)
+		}
+
+		if err != nil {
+			log.Warn("Error on button ", ab, " for action ", action, ": ", err)
+		}
+	}
+}
+
+func bindDrag(c *store.Client, button string, tr *desktop.Tracker, step func(*desktop.Workspace, common.Point), end func(*desktop.Workspace, common.Point)) error {
+	if _, _, err := mousebind.ParseString(store.X, button); err != nil {
+		return err
+	}
+
+	// Drag tiled clients only
+	begin := func(X *xgbutil.XUtil, rx, ry, ex, ey int) (bool, xproto.Cursor) {
+		ws := tr.ClientWorkspace(c)
+		if ws == nil || ws.TilingDisabled() || c.IsFloating() {
+			return false, 0
+		}
+		log.Info("Client drag started [", c.Latest.Class, "]")
+
+		return true, 0
+	}
+
+	// Handle pointer motion and release
+	handle := func(fun func(*desktop.Workspace, common.Point)) xgbutil.MouseDragFun {
+		return func(X *xgbutil.XUtil, rx, ry, ex, ey int) {
+			ws := tr.ClientWorkspace(c)
+			if fun == nil || ws == nil || ws.TilingDisabled() {
+				return
+			}
+			fun(ws, *common.CreatePoint(rx, ry))
+		}
+	}
+
+	mousebind.Drag(store.X, store.X.Dummy(), c.Window.Id, button, true, begin, handle(step), handle(end))
+
+	return nil
+}
+
+func moveClient(c *store.Client, ws *desktop.Workspace, p common.Point, tr *desktop.Tracker) {
+	log.Info("Client drag released [", c.Latest.Class, "]")
+
+	// Move client to screen under pointer
+	screen := store.ScreenGet(p)
+	if screen != c.Latest.Location.Screen {
+		c.MoveToScreen(uint32(screen))
+		return
+	}
+
+	// Swap client with tile under pointer
+	co := tr.ClientAt(ws, p)
+	if co == nil || co == c {
+		return
+	}
+	ws.ActiveLayout().GetManager().SwapClient(c, co)
+	tr.Tile(ws)
+}
+
+func resizeClient(c *store.Client, ws *desktop.Workspace, p common.Point, tr *desktop.Tracker, released bool) {
+	x, y, w, h := c.OuterGeometry()
+
+	// Obtain resized edges from pointer position on drag start
+	if edges == nil {
+		edges = &store.Directions{
+			Top:    p.Y < y+h/2,
+			Right:  p.X >= x+w/2,
+			Bottom: p.Y >= y+h/2,
+			Left:   p.X < x+w/2,
+		}
+	}
+	dir := *edges
+	if released {
+		edges = nil
+	}
+
+	// Coalesce pointer motion events
+	if !released && time.Since(resized) < 50*time.Millisecond {
+		return
+	}
+	resized = time.Now()
+
+	// Move resized edges to pointer position
+	if dir.Left {
+		x, w = p.X, x+w-p.X
+	} else if dir.Right {
+		w = p.X - x
+	}
+	if dir.Top {
+		y, h = p.Y, y+h-p.Y
+	} else if dir.Bottom {
+		h = p.Y - y
+	}
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	// Update layout proportions
+	ws.ActiveLayout().UpdateProportions(c, &common.Geometry{X: x, Y: y, Width: w, Height: h}, &dir)
+	tr.Tile(ws)
+}
+
 func resetTracker(tr *desktop.Tracker) {
 	if pointer == nil || pointer.Position != store.Pointer.Position {
 		return
 	}
 
 	// Reset tracker handler
-	if !tr.Handlers.MoveClient.Active() {
+	if !tr.Handlers.MoveClient.Active() && !tr.Handlers.SnapClient.Active() {
 		tr.Handlers.Reset()
 	}
 }
@@ -72,11 +219,17 @@ func updateCorner(tr *desktop.Tracker) {
 		return
 	}
 
+	// Ignore corners while dragging windows
+	if tr.Handlers.MoveClient.Active() || tr.Handlers.SnapClient.Active() {
+		hc.Fired = time.Time{}
+		return
+	}
+
 	// Communicate corner change
 	tr.Channels.Event <- "corner_change"
 
 	// Execute action
-	ExecuteAction(common.Config.Corners[hc.Name], tr, tr.ActiveWorkspace())
+	ExecuteAction(common.CornerAction(hc.Name, store.Workplace.CurrentDesktop, hc.Screen), tr, tr.ActiveWorkspace())
 }
 
 func updateFocus(tr *desktop.Tracker) {
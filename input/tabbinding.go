@@ -0,0 +1,17 @@
+package input
+
+import (
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/ui"
+)
+
+func BindTabs(tr *desktop.Tracker) {
+
+	// Attach tracker events
+	OnEvent(func(event string) {
+		if common.IsInList(event, []string{"clients_change", "windows_change", "workplace_change", "title_change"}) {
+			ui.ShowTabs(tr)
+		}
+	})
+}
@@ -279,6 +279,19 @@ func onExecute(tr *desktop.Tracker, action string) {
 	onActivate(tr)
 }
 
+func onModeChange(mode string) {
+	if len(common.Config.TilingIcon) == 0 {
+		return
+	}
+
+	// Show active key mode in tooltip
+	tooltip := fmt.Sprintf("%s - tiling manager", common.Build.Name)
+	if len(mode) > 0 {
+		tooltip = fmt.Sprintf("%s [%s]", tooltip, mode)
+	}
+	systray.SetTooltip(tooltip)
+}
+
 func onActivate(tr *desktop.Tracker) {
 	ws := tr.ActiveWorkspace()
 	al := ws.ActiveLayout()
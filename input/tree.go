@@ -0,0 +1,181 @@
+package input
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"encoding/json"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/store"
+)
+
+type TreeWorkspace struct {
+	Desktop     uint                 // Workspace desktop index
+	Screen      uint                 // Workspace screen index
+	Layout      string               // Active layout name
+	Tiling      bool                 // Tiling is enabled
+	Proportions map[string][]float64 // Proportions in use by the active layout
+	Masters     []TreeClient         // Ordered master clients
+	Slaves      []TreeClient         // Ordered slave clients
+}
+
+type TreeClient struct {
+	Id       xproto.Window   // Client window id
+	Class    string          // Client window application name
+	Title    string          // Client window title name
+	Geometry common.Geometry // Client window geometry
+}
+
+func treeClients(cs []*store.Client) []TreeClient {
+	clients := []TreeClient{}
+	for _, c := range cs {
+		clients = append(clients, TreeClient{
+			Id:       c.Window.Id,
+			Class:    c.Latest.Class,
+			Title:    c.Latest.Name,
+			Geometry: c.Latest.Dimensions.Geometry,
+		})
+	}
+	return clients
+}
+
+func Tree(desktop int, screen int, class string, asJson bool) {
+	line, err := treeReply()
+	if err != nil {
+		fatal("Error receiving tree", err)
+	}
+
+	// Parse reply
+	var reply struct {
+		Type string
+		Data struct {
+			Message    string
+			Workspaces []TreeWorkspace
+		}
+	}
+	err = json.Unmarshal([]byte(line), &reply)
+	if err != nil {
+		fatal("Error parsing tree", err)
+	}
+	if reply.Type == "Error" {
+		fatal("Error receiving tree", errors.New(reply.Data.Message))
+	}
+
+	// Filter workspaces and clients
+	workspaces := []TreeWorkspace{}
+	proportions := []string{}
+	for _, ws := range reply.Data.Workspaces {
+		if desktop >= 0 && int(ws.Desktop) != desktop {
+			continue
+		}
+		if screen >= 0 && int(ws.Screen) != screen {
+			continue
+		}
+
+		// Format proportions before clients are filtered
+		p := formatProportions(ws)
+		if len(class) > 0 {
+			ws.Masters = filterClients(ws.Masters, class)
+			ws.Slaves = filterClients(ws.Slaves, class)
+			if len(ws.Masters)+len(ws.Slaves) == 0 {
+				continue
+			}
+		}
+		workspaces = append(workspaces, ws)
+		proportions = append(proportions, p)
+	}
+
+	// Print json tree
+	if asJson {
+		print("Result", "Tree", common.Map{"Workspaces": workspaces})
+		return
+	}
+
+	// Print text tree
+	for i, ws := range workspaces {
+		state := "enabled"
+		if !ws.Tiling {
+			state = "disabled"
+		}
+		fmt.Printf("desktop %d screen %d: %s (%s)\n", ws.Desktop, ws.Screen, ws.Layout, state)
+		fmt.Printf("  proportions: %s\n", proportions[i])
+		for _, c := range ws.Masters {
+			fmt.Printf("  master %s\n", formatClient(c))
+		}
+		for _, c := range ws.Slaves {
+			fmt.Printf("  slave  %s\n", formatClient(c))
+		}
+	}
+}
+
+func treeReply() (string, error) {
+
+	// Request tree via dbus
+	conn, err := connect()
+	if err == nil {
+		defer conn.Close()
+		call := conn.Object(iface, opath).Call(fmt.Sprintf("%s.%s", iface, "TreeGet"), 0)
+		if call.Err == nil {
+			var reply string
+			call.Store(&reply)
+			return reply, nil
+		}
+	}
+
+	// Request tree via ipc
+	sock, err := net.Dial("unix", common.SocketFilePath())
+	if err != nil {
+		return "", fmt.Errorf("%s is not running: %s", common.Build.Name, err)
+	}
+	defer sock.Close()
+
+	err = request(sock, IpcRequest{Method: "TreeGet"})
+	if err != nil {
+		return "", err
+	}
+
+	return bufio.NewReaderSize(sock, ipcBufferSize).ReadString('\n')
+}
+
+func filterClients(cs []TreeClient, class string) []TreeClient {
+	clients := []TreeClient{}
+	for _, c := range cs {
+		if strings.Contains(strings.ToLower(c.Class), strings.ToLower(class)) {
+			clients = append(clients, c)
+		}
+	}
+	return clients
+}
+
+func formatProportions(ws TreeWorkspace) string {
+	parts := []string{}
+
+	// Proportions in the order of their kinds
+	for _, kind := range []string{"master_slave", "master_master", "slave_slave", "columns", "rows", "splits"} {
+		ps, ok := ws.Proportions[kind]
+		if !ok || len(ps) == 0 {
+			continue
+		}
+		values := make([]string, len(ps))
+		for i, value := range ps {
+			values[i] = fmt.Sprintf("%.2f", value)
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s", kind, strings.Join(values, ",")))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func formatClient(c TreeClient) string {
+	x, y, w, h := c.Geometry.Pieces()
+	return fmt.Sprintf("%d %s %q %dx%d+%d+%d", c.Id, c.Class, c.Title, w, h, x, y)
+}
@@ -0,0 +1,238 @@
+package integration
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/jezek/xgb/randr"
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/jezek/xgbutil"
+	"github.com/jezek/xgbutil/ewmh"
+	"github.com/jezek/xgbutil/xevent"
+	"github.com/jezek/xgbutil/xprop"
+	"github.com/jezek/xgbutil/xwindow"
+)
+
+type stub struct {
+	X        *xgbutil.XUtil  // Stub window manager connection
+	Check    xproto.Window   // Supporting window manager check window
+	Clients  []xproto.Window // Managed client windows in stacking order
+	Desktops uint            // Number of virtual desktops
+	Width    int             // Width of the root window
+	Height   int             // Height of the root window
+	mutex    sync.Mutex      // Lock for managed client windows
+}
+
+func startStub(desktops uint, width int, height int) (*stub, error) {
+	X, err := xgbutil.NewConn()
+	if err != nil {
+		return nil, err
+	}
+	s := &stub{X: X, Desktops: desktops, Width: width, Height: height}
+
+	// Check for a connected RandR output
+	err = randr.Init(X.Conn())
+	if err != nil {
+		return nil, err
+	}
+	resources, err := randr.GetScreenResources(X.Conn(), X.RootWin()).Reply()
+	if err != nil || len(resources.Outputs) == 0 {
+		return nil, fmt.Errorf("RandR outputs not available: %v", err)
+	}
+
+	// Create supporting window manager check window
+	check, err := xwindow.Generate(X)
+	if err != nil {
+		return nil, err
+	}
+	err = check.CreateChecked(X.RootWin(), -1, -1, 1, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	s.Check = check.Id
+	ewmh.SupportingWmCheckSet(X, X.RootWin(), check.Id)
+	ewmh.SupportingWmCheckSet(X, check.Id, check.Id)
+	ewmh.WmNameSet(X, check.Id, "stub")
+
+	// Set root window properties
+	ewmh.SupportedSet(X, []string{
+		"_NET_SUPPORTED",
+		"_NET_SUPPORTING_WM_CHECK",
+		"_NET_CLIENT_LIST",
+		"_NET_CLIENT_LIST_STACKING",
+		"_NET_ACTIVE_WINDOW",
+		"_NET_NUMBER_OF_DESKTOPS",
+		"_NET_CURRENT_DESKTOP",
+		"_NET_DESKTOP_GEOMETRY",
+		"_NET_DESKTOP_VIEWPORT",
+		"_NET_WORKAREA",
+		"_NET_MOVERESIZE_WINDOW",
+		"_NET_CLOSE_WINDOW",
+		"_NET_WM_DESKTOP",
+		"_NET_WM_STATE",
+	})
+	viewports := make([]ewmh.DesktopViewport, desktops)
+	workareas := make([]ewmh.Workarea, desktops)
+	for i := range workareas {
+		workareas[i] = ewmh.Workarea{X: 0, Y: 0, Width: uint(width), Height: uint(height)}
+	}
+	ewmh.NumberOfDesktopsSet(X, desktops)
+	ewmh.CurrentDesktopSet(X, 0)
+	ewmh.DesktopGeometrySet(X, &ewmh.DesktopGeometry{Width: width, Height: height})
+	ewmh.DesktopViewportSet(X, viewports)
+	ewmh.WorkareaSet(X, workareas)
+	ewmh.ActiveWindowSet(X, 0)
+	s.update()
+
+	// Listen to root window events
+	xwindow.New(X, X.RootWin()).Listen(xproto.EventMaskSubstructureNotify)
+	xevent.HookFun(func(X *xgbutil.XUtil, ev interface{}) bool {
+		switch e := ev.(type) {
+		case xproto.MapNotifyEvent:
+			if e.Event == X.RootWin() && !e.OverrideRedirect {
+				s.manage(e.Window)
+			}
+		case xproto.DestroyNotifyEvent:
+			if e.Event == X.RootWin() {
+				s.unmanage(e.Window)
+			}
+		case xproto.ClientMessageEvent:
+			s.message(e)
+		}
+		return true
+	}).Connect(X)
+
+	// Run stub event loop
+	X.Sync()
+	go xevent.Main(X)
+
+	return s, nil
+}
+
+func (s *stub) manage(w xproto.Window) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, c := range s.Clients {
+		if c == w {
+			return
+		}
+	}
+
+	// Place window on current desktop
+	if _, err := ewmh.WmDesktopGet(s.X, w); err != nil {
+		desktop, _ := ewmh.CurrentDesktopGet(s.X)
+		ewmh.WmDesktopSet(s.X, w, desktop)
+	}
+
+	// Add and activate window
+	s.Clients = append(s.Clients, w)
+	s.update()
+	ewmh.ActiveWindowSet(s.X, w)
+}
+
+func (s *stub) unmanage(w xproto.Window) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, c := range s.Clients {
+		if c != w {
+			continue
+		}
+
+		// Remove window and activate topmost window
+		s.Clients = append(s.Clients[:i], s.Clients[i+1:]...)
+		s.update()
+		if active, _ := ewmh.ActiveWindowGet(s.X); active == w {
+			top := xproto.Window(0)
+			if len(s.Clients) > 0 {
+				top = s.Clients[len(s.Clients)-1]
+			}
+			ewmh.ActiveWindowSet(s.X, top)
+		}
+		return
+	}
+}
+
+func (s *stub) update() {
+	clients := append([]xproto.Window{s.Check}, s.Clients...)
+	ewmh.ClientListSet(s.X, clients)
+	ewmh.ClientListStackingSet(s.X, clients)
+}
+
+func (s *stub) message(e xproto.ClientMessageEvent) {
+	name, err := xprop.AtomName(s.X, e.Type)
+	if err != nil {
+		return
+	}
+	data := e.Data.Data32
+
+	switch name {
+	case "_NET_MOVERESIZE_WINDOW":
+		flags := 0
+		if data[0]&(1<<8) != 0 {
+			flags |= xproto.ConfigWindowX
+		}
+		if data[0]&(1<<9) != 0 {
+			flags |= xproto.ConfigWindowY
+		}
+		if data[0]&(1<<10) != 0 {
+			flags |= xproto.ConfigWindowWidth
+		}
+		if data[0]&(1<<11) != 0 {
+			flags |= xproto.ConfigWindowHeight
+		}
+		x, y, w, h := int(int32(data[1])), int(int32(data[2])), int(data[3]), int(data[4])
+		xwindow.New(s.X, e.Window).Configure(flags, x, y, w, h, 0, 0)
+	case "_NET_WM_STATE":
+		states, _ := ewmh.WmStateGet(s.X, e.Window)
+		updated := states
+		for _, atom := range data[1:3] {
+			if atom == 0 {
+				continue
+			}
+			state, err := xprop.AtomName(s.X, xproto.Atom(atom))
+			if err != nil {
+				continue
+			}
+			updated = changeState(updated, state, int(data[0]))
+		}
+		if fmt.Sprint(updated) != fmt.Sprint(states) {
+			ewmh.WmStateSet(s.X, e.Window, updated)
+		}
+	case "_NET_WM_DESKTOP":
+		desktop, err := ewmh.WmDesktopGet(s.X, e.Window)
+		if err != nil || desktop != uint(data[0]) {
+			ewmh.WmDesktopSet(s.X, e.Window, uint(data[0]))
+		}
+	case "_NET_ACTIVE_WINDOW":
+		ewmh.ActiveWindowSet(s.X, e.Window)
+	case "_NET_CURRENT_DESKTOP":
+		if uint(data[0]) < s.Desktops {
+			ewmh.CurrentDesktopSet(s.X, uint(data[0]))
+		}
+	case "_NET_CLOSE_WINDOW":
+		xproto.DestroyWindow(s.X.Conn(), e.Window)
+	}
+}
+
+func changeState(states []string, state string, action int) []string {
+	index := -1
+	for i, s := range states {
+		if s == state {
+			index = i
+		}
+	}
+
+	// Remove, add or toggle state
+	remove := action == ewmh.StateRemove || (action == ewmh.StateToggle && index >= 0)
+	if remove && index >= 0 {
+		return append(append([]string{}, states[:index]...), states[index+1:]...)
+	}
+	if !remove && index < 0 {
+		return append(append([]string{}, states...), state)
+	}
+
+	return states
+}
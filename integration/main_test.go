@@ -0,0 +1,322 @@
+package integration
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"os/exec"
+	"path/filepath"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/jezek/xgbutil"
+	"github.com/jezek/xgbutil/icccm"
+	"github.com/jezek/xgbutil/xevent"
+	"github.com/jezek/xgbutil/xwindow"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	app     *xgbutil.XUtil    // X connection for test application windows
+	wm      *stub             // Stub window manager instance
+	tr      *desktop.Tracker  // Workspace tracker instance
+	loop    sync.Mutex        // Lock between event loop and tests
+	timeout = 5 * time.Second // Maximum wait time for conditions
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv("CORTILE_INTEGRATION") != "1" {
+		fmt.Println("Skip integration tests (set CORTILE_INTEGRATION=1 to run)")
+		os.Exit(0)
+	}
+
+	// Fail if the virtual X server can not be set up
+	cleanup, err := setup()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error setting up integration tests:", err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	cleanup()
+	os.Exit(code)
+}
+
+func setup() (func(), error) {
+	path, err := exec.LookPath("Xvfb")
+	if err != nil {
+		return nil, fmt.Errorf("Xvfb not available: %s", err)
+	}
+
+	// Start virtual X server
+	xvfb, display, err := startXvfb(path)
+	if err != nil {
+		return nil, err
+	}
+	os.Setenv("DISPLAY", display)
+
+	temp, err := os.MkdirTemp("", "cortile-integration-")
+	if err != nil {
+		xvfb.Process.Kill()
+		return nil, err
+	}
+	cleanup := func() {
+		xvfb.Process.Kill()
+		xvfb.Wait()
+		os.RemoveAll(temp)
+	}
+
+	// Start stub window manager
+	wm, err = startStub(2, 1920, 1080)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	app, err = xgbutil.NewConn()
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	// Init cortile configuration
+	toml, err := os.ReadFile(filepath.Join("..", "config.toml"))
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	log.SetLevel(log.WarnLevel)
+	common.InitInfo("cortile", "test", "0.0.0", "local", "unknown", "github.com/leukipp/cortile", "")
+	common.InitFiles(toml, nil)
+	common.Args.Cache = "disabled"
+	common.Args.Config = filepath.Join(temp, "config.toml")
+	common.InitConfig()
+
+	// Simplify geometries for assertions
+	common.Config.TilingEnabled = true
+	common.Config.WindowGapSize = 0
+	common.Config.WindowSession = false
+	common.Config.EdgeMargin = []int{0, 0, 0, 0}
+	common.Config.EdgeMarginPrimary = []int{0, 0, 0, 0}
+
+	// Init root properties and tracker
+	store.InitRoot()
+	tr = desktop.CreateTracker()
+	go func() {
+		for range tr.Channels.Event {
+		}
+	}()
+	tr.Update()
+
+	// Run cortile event loop, interleaved with tests
+	before, after, quit := xevent.MainPing(store.X)
+	go func() {
+		for {
+			select {
+			case <-before:
+				loop.Lock()
+				<-after
+				loop.Unlock()
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	return cleanup, nil
+}
+
+func startXvfb(path string) (*exec.Cmd, string, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, "", err
+	}
+	defer r.Close()
+
+	// Let Xvfb choose a free display and report it on fd 3
+	cmd := exec.Command(path, "-displayfd", "3", "-screen", "0", "1920x1080x24", "-nolisten", "tcp", "+extension", "RANDR")
+	cmd.ExtraFiles = []*os.File{w}
+	err = cmd.Start()
+	w.Close()
+	if err != nil {
+		return nil, "", fmt.Errorf("Xvfb failed to start: %s", err)
+	}
+
+	// Wait for display number
+	ch := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(r).ReadString('\n')
+		ch <- strings.TrimSpace(line)
+	}()
+	select {
+	case display := <-ch:
+		if len(display) == 0 {
+			cmd.Process.Kill()
+			return nil, "", fmt.Errorf("Xvfb failed to report display")
+		}
+		return cmd, ":" + display, nil
+	case <-time.After(timeout):
+		cmd.Process.Kill()
+		return nil, "", fmt.Errorf("Xvfb timed out")
+	}
+}
+
+func do(fun func()) {
+	loop.Lock()
+	defer loop.Unlock()
+	fun()
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		ok := false
+		do(func() { ok = cond() })
+		if ok {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Timeout waiting for %s", what)
+}
+
+func openWindows(t *testing.T, n int) []xproto.Window {
+	t.Helper()
+
+	// Start each test with fresh layouts
+	do(func() {
+		for _, ws := range tr.Workspaces {
+			ws.ResetLayouts()
+			ws.EnableTiling()
+		}
+	})
+
+	windows := []xproto.Window{}
+	for i := 0; i < n; i++ {
+		win, err := xwindow.Generate(app)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = win.CreateChecked(app.RootWin(), 100+i*10, 100+i*10, 400, 300, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Set class and name and map window
+		name := fmt.Sprintf("%s-%d", t.Name(), i)
+		icccm.WmClassSet(app, win.Id, &icccm.WmClass{Instance: name, Class: name})
+		icccm.WmNameSet(app, win.Id, name)
+		win.Map()
+		windows = append(windows, win.Id)
+
+		// Wait until window is tracked
+		waitFor(t, fmt.Sprintf("tracking of %s", name), func() bool {
+			_, ok := tr.Clients[win.Id]
+			return ok
+		})
+	}
+
+	// Close windows after test
+	t.Cleanup(func() {
+		for _, w := range windows {
+			xproto.DestroyWindow(app.Conn(), w)
+		}
+		app.Sync()
+		waitFor(t, "untracking of windows", func() bool {
+			for _, w := range windows {
+				if _, ok := tr.Clients[w]; ok {
+					return false
+				}
+			}
+			return true
+		})
+	})
+
+	return windows
+}
+
+func splitScreens(t *testing.T) {
+	t.Helper()
+
+	// Use physical heads if the server provides two RandR outputs
+	var displays store.XDisplays
+	do(func() { displays = store.Workplace.Displays })
+	if len(displays.Screens) > 1 {
+		return
+	}
+
+	// Xvfb provides a single RandR output and clients can not add outputs,
+	// so split it into two heads (RandR monitors are not read by cortile)
+	do(func() {
+		store.Workplace.Displays = store.XDisplays{
+			Name:     displays.Name,
+			Screens:  splitHeads(displays.Screens),
+			Desktops: splitHeads(displays.Desktops),
+		}
+		store.Workplace.Displays.Corners = store.CreateCorners(store.Workplace.Displays.Screens)
+		store.Workplace.ScreenCount = uint(len(store.Workplace.Displays.Screens))
+		tr.Reset()
+	})
+
+	// Restore original heads after test
+	t.Cleanup(func() {
+		do(func() {
+			store.Workplace.Displays = displays
+			store.Workplace.ScreenCount = uint(len(displays.Screens))
+			tr.Reset()
+		})
+	})
+}
+
+func splitHeads(heads []store.XHead) []store.XHead {
+	split := []store.XHead{}
+	for _, head := range heads {
+		x, y, w, h := head.Geometry.Pieces()
+		left, right := head, head
+		left.Geometry = common.Geometry{X: x, Y: y, Width: w / 2, Height: h}
+		right.Geometry = common.Geometry{X: x + w/2, Y: y, Width: w - w/2, Height: h}
+		right.Id, right.Name, right.Primary = head.Id+1, head.Name+"-right", false
+		split = append(split, left, right)
+	}
+	return split
+}
+
+func geometry(w xproto.Window) common.Geometry {
+	geom, err := xwindow.RawGeometry(app, xproto.Drawable(w))
+	if err != nil {
+		return common.Geometry{}
+	}
+	return common.Geometry{X: geom.X(), Y: geom.Y(), Width: geom.Width(), Height: geom.Height()}
+}
+
+func waitGeometries(t *testing.T, expected func() map[xproto.Window]common.Geometry) {
+	t.Helper()
+	var want map[xproto.Window]common.Geometry
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		do(func() { want = expected() })
+		matched := true
+		for w, g := range want {
+			matched = matched && geometry(w) == g
+		}
+		if matched {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	for w, g := range want {
+		if got := geometry(w); got != g {
+			t.Errorf("Window %d has geometry %+v, expected %+v", w, got, g)
+		}
+	}
+	t.FailNow()
+}
@@ -0,0 +1,195 @@
+package integration
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
+)
+
+func tile(name string) *desktop.Workspace {
+	var ws *desktop.Workspace
+	do(func() {
+		ws = tr.WorkspaceAt(0, 0)
+		ws.SetLayoutByName(name)
+		tr.Tile(ws)
+	})
+	return ws
+}
+
+func half(size int) int {
+	return int(math.Round(float64(size) * 0.5))
+}
+
+func verticalGeometries(ws *desktop.Workspace) map[xproto.Window]common.Geometry {
+	dx, dy, dw, dh := store.DesktopGeometry(0).Pieces()
+	mg := ws.ActiveLayout().GetManager()
+	geoms := map[xproto.Window]common.Geometry{}
+
+	// Masters share the left half, slaves share the right half
+	mw := half(dw)
+	if len(mg.Slaves.Stacked) == 0 {
+		mw = dw
+	}
+	for i, c := range mg.Masters.Stacked {
+		mh := int(math.Round(float64(dh) / float64(len(mg.Masters.Stacked))))
+		geoms[c.Window.Id] = common.Geometry{X: dx, Y: dy + i*mh, Width: mw, Height: mh}
+	}
+	for i, c := range mg.Slaves.Stacked {
+		sh := int(math.Round(float64(dh) / float64(len(mg.Slaves.Stacked))))
+		geoms[c.Window.Id] = common.Geometry{X: dx + mw, Y: dy + i*sh, Width: dw - mw, Height: sh}
+	}
+
+	return geoms
+}
+
+func TestVerticalLayout(t *testing.T) {
+	openWindows(t, 3)
+
+	ws := tile("vertical-left")
+	waitGeometries(t, func() map[xproto.Window]common.Geometry {
+		return verticalGeometries(ws)
+	})
+}
+
+func TestHorizontalLayout(t *testing.T) {
+	openWindows(t, 3)
+
+	ws := tile("horizontal-top")
+	waitGeometries(t, func() map[xproto.Window]common.Geometry {
+		dx, dy, dw, dh := store.DesktopGeometry(0).Pieces()
+		mg := ws.ActiveLayout().GetManager()
+		geoms := map[xproto.Window]common.Geometry{}
+
+		// Masters share the top half, slaves share the bottom half
+		mh := half(dh)
+		for i, c := range mg.Masters.Stacked {
+			mw := int(math.Round(float64(dw) / float64(len(mg.Masters.Stacked))))
+			geoms[c.Window.Id] = common.Geometry{X: dx + i*mw, Y: dy, Width: mw, Height: mh}
+		}
+		for i, c := range mg.Slaves.Stacked {
+			sw := int(math.Round(float64(dw) / float64(len(mg.Slaves.Stacked))))
+			geoms[c.Window.Id] = common.Geometry{X: dx + i*sw, Y: dy + mh, Width: sw, Height: dh - mh}
+		}
+
+		return geoms
+	})
+}
+
+func TestSwapClient(t *testing.T) {
+	openWindows(t, 3)
+
+	ws := tile("vertical-left")
+	waitGeometries(t, func() map[xproto.Window]common.Geometry {
+		return verticalGeometries(ws)
+	})
+
+	// Swap master with first slave
+	var master, slave xproto.Window
+	var before map[xproto.Window]common.Geometry
+	do(func() {
+		mg := ws.ActiveLayout().GetManager()
+		before = verticalGeometries(ws)
+		master, slave = mg.Masters.Stacked[0].Window.Id, mg.Slaves.Stacked[0].Window.Id
+		mg.SwapClient(mg.Masters.Stacked[0], mg.Slaves.Stacked[0])
+		tr.Tile(ws)
+	})
+	waitGeometries(t, func() map[xproto.Window]common.Geometry {
+		return map[xproto.Window]common.Geometry{
+			master: before[slave],
+			slave:  before[master],
+		}
+	})
+}
+
+func TestMasterChanges(t *testing.T) {
+	openWindows(t, 3)
+
+	ws := tile("vertical-left")
+
+	// Make last slave the new master
+	var last xproto.Window
+	do(func() {
+		mg := ws.ActiveLayout().GetManager()
+		c := mg.Slaves.Stacked[len(mg.Slaves.Stacked)-1]
+		last = c.Window.Id
+		mg.MakeMaster(c)
+		tr.Tile(ws)
+	})
+	waitGeometries(t, func() map[xproto.Window]common.Geometry {
+		mg := ws.ActiveLayout().GetManager()
+		if mg.Masters.Stacked[0].Window.Id != last {
+			t.Fatalf("Window %d is not master", last)
+		}
+		return verticalGeometries(ws)
+	})
+
+	// Increase number of masters
+	do(func() {
+		ws.ActiveLayout().GetManager().IncreaseMaster()
+		tr.Tile(ws)
+	})
+	waitGeometries(t, func() map[xproto.Window]common.Geometry {
+		mg := ws.ActiveLayout().GetManager()
+		if len(mg.Masters.Stacked) != 2 || len(mg.Slaves.Stacked) != 1 {
+			t.Fatalf("Expected 2 masters and 1 slave, got %d and %d", len(mg.Masters.Stacked), len(mg.Slaves.Stacked))
+		}
+		return verticalGeometries(ws)
+	})
+}
+
+func TestDesktopMove(t *testing.T) {
+	windows := openWindows(t, 2)
+
+	ws := tile("vertical-left")
+
+	// Move window to second desktop
+	do(func() {
+		tr.Clients[windows[1]].MoveToDesktop(1)
+	})
+	waitFor(t, "window on second desktop", func() bool {
+		c, ok := tr.Clients[windows[1]]
+		return ok && tr.ClientWorkspace(c).Location.Desktop == 1
+	})
+
+	// Remaining window fills the first desktop
+	waitGeometries(t, func() map[xproto.Window]common.Geometry {
+		return verticalGeometries(ws)
+	})
+	do(func() {
+		if n := len(ws.ActiveLayout().GetManager().Clients(store.Stacked)); n != 1 {
+			t.Fatalf("Expected 1 window on first desktop, got %d", n)
+		}
+	})
+}
+
+func TestScreenMove(t *testing.T) {
+	splitScreens(t)
+	windows := openWindows(t, 2)
+
+	tile("vertical-left")
+	do(func() {
+		tr.WorkspaceAt(0, 1).SetLayoutByName("vertical-left")
+	})
+
+	// Move window to second screen
+	do(func() {
+		tr.Clients[windows[1]].MoveToScreen(1)
+	})
+	waitFor(t, "window on second screen", func() bool {
+		c, ok := tr.Clients[windows[1]]
+		return ok && tr.ClientWorkspace(c).Location.Screen == 1
+	})
+
+	// Each window fills the desktop of its screen
+	waitGeometries(t, func() map[xproto.Window]common.Geometry {
+		return map[xproto.Window]common.Geometry{
+			windows[0]: *store.DesktopGeometry(0),
+			windows[1]: *store.DesktopGeometry(1),
+		}
+	})
+}
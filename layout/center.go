@@ -0,0 +1,298 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type CenterLayout struct {
+	Name           string // Layout name
+	*store.Manager        // Layout store manager
+}
+
+type column struct {
+	X       int             // Column x position
+	Width   int             // Column width dimension
+	Last    bool            // Column is at the right border
+	Rows    int             // Column number of rows
+	Clients []*store.Client // Column clients
+}
+
+func CreateVerticalCenterLayout(loc store.Location) *CenterLayout {
+	layout := &CenterLayout{
+		Name:    "vertical-center",
+		Manager: store.CreateManager(loc),
+	}
+	layout.Reset()
+	return layout
+}
+
+func (l *CenterLayout) Reset() {
+	mg := store.CreateManager(*l.Location)
+
+	// Reset number of masters
+	for l.Masters.Maximum < mg.Masters.Maximum {
+		l.IncreaseMaster()
+	}
+	for l.Masters.Maximum > mg.Masters.Maximum {
+		l.DecreaseMaster()
+	}
+
+	// Reset number of slaves
+	for l.Slaves.Maximum < mg.Slaves.Maximum {
+		l.IncreaseSlave()
+	}
+	for l.Slaves.Maximum > mg.Slaves.Maximum {
+		l.DecreaseSlave()
+	}
+
+	// Reset layout proportions
+	l.Manager.Proportions = mg.Proportions
+	for n := 1; n <= 3; n++ {
+		l.Proportions.Columns[n] = proportions(l.Proportions.Columns, n)
+	}
+}
+
+func (l *CenterLayout) Apply() {
+	clients := l.Clients(store.Stacked)
+
+	_, dy, dw, dh := store.DesktopGeometry(l.Location.Screen).Pieces()
+	gap := common.Config.WindowGapSize
+
+	csize := len(clients)
+
+	log.Info("Tile ", csize, " windows with ", l.Name, " layout [workspace-", l.Location.Desktop, "-", l.Location.Screen, "]")
+
+	// Calculate column dimensions
+	columns := l.columns()
+
+	minpw := common.Config.ProportionMin
+	if len(columns) == 1 {
+		minpw = 1.0
+	}
+
+	// Column area layout
+	for _, col := range columns {
+		n := col.Rows
+		if n == 0 {
+			continue
+		}
+
+		// Adjust sizes and proportions
+		minph := common.Config.ProportionMin
+		if n == 1 {
+			minph = 1.0
+		}
+		ps := l.Proportions.SlaveSlave[n]
+		if l.IsMaster(col.Clients[0]) {
+			ps = l.Proportions.MasterMaster[n]
+		}
+
+		cx, cw := col.X+gap, col.Width-gap
+		if col.Last {
+			cw -= gap
+		}
+
+		cy := 0
+		for i, c := range col.Clients {
+
+			// Reset y position
+			if i%n == 0 {
+				cy = dy + gap
+			}
+
+			// Limit minimum dimensions
+			minw := int(math.Round(float64(dw-2*gap) * minpw))
+			minh := int(math.Round(float64(dh-(n+1)*gap) * minph))
+			c.Limit(minw, minh)
+
+			// Move and resize client
+			ch := int(math.Round(float64(dh-(n+1)*gap) * ps[i%n]))
+			c.MoveWindow(cx, cy, cw, ch)
+
+			// Add y offset
+			cy += ch + gap
+		}
+	}
+}
+
+func (l *CenterLayout) UpdateProportions(c *store.Client, g *common.Geometry, d *store.Directions) {
+	_, _, dw, dh := store.DesktopGeometry(l.Location.Screen).Pieces()
+	_, _, cw, ch := g.Pieces()
+
+	gap := common.Config.WindowGapSize
+
+	// Obtain column of client
+	columns := l.columns()
+	ci, ri := -1, -1
+	for i, col := range columns {
+		for j, cc := range col.Clients {
+			if cc.Window.Id == c.Window.Id {
+				ci, ri = i, j
+			}
+		}
+	}
+	if ci < 0 {
+		return
+	}
+	col := columns[ci]
+	n := col.Rows
+	ri %= n
+
+	// Set column proportions
+	px := float64(cw+gap) / float64(dw)
+	if col.Last {
+		px = float64(cw+2*gap) / float64(dw)
+	}
+	ps := proportions(l.Proportions.Columns, len(columns))
+	if d.Left && ci > 0 {
+		l.Manager.SetProportions(ps, px, ci, ci-1)
+	} else if d.Right && ci < len(columns)-1 {
+		l.Manager.SetProportions(ps, px, ci, ci+1)
+	}
+
+	// Set row proportions
+	py := float64(ch) / float64(dh-(n+1)*gap)
+	ps = l.Proportions.SlaveSlave[n]
+	if l.IsMaster(c) {
+		ps = l.Proportions.MasterMaster[n]
+	}
+	if d.Top {
+		l.Manager.SetProportions(ps, py, ri, ri-1)
+	} else if d.Bottom {
+		l.Manager.SetProportions(ps, py, ri, ri+1)
+	}
+}
+
+func (l *CenterLayout) IncreaseProportion() {
+
+	// Increase center proportion
+	l.setCenterProportion(l.centerProportion() + common.Config.ProportionStep)
+}
+
+func (l *CenterLayout) DecreaseProportion() {
+
+	// Decrease center proportion
+	l.setCenterProportion(l.centerProportion() - common.Config.ProportionStep)
+}
+
+func (l *CenterLayout) SetProportion(p float64) {
+
+	// Set center proportion
+	l.setCenterProportion(p)
+}
+
+func (l *CenterLayout) GetProportions() map[string][]float64 {
+	columns := l.columns()
+
+	// Proportions in use for the current client counts
+	ps := map[string][]float64{}
+	if len(columns) > 1 {
+		ps["columns"] = proportions(l.Proportions.Columns, len(columns))
+	}
+	for _, col := range columns {
+		if col.Rows < 2 {
+			continue
+		}
+		if l.IsMaster(col.Clients[0]) {
+			ps["master_master"] = l.Proportions.MasterMaster[col.Rows]
+		} else {
+			ps["slave_slave"] = l.Proportions.SlaveSlave[col.Rows]
+		}
+	}
+
+	return ps
+}
+
+func (l *CenterLayout) GetManager() *store.Manager {
+	return l.Manager
+}
+
+func (l *CenterLayout) GetName() string {
+	return l.Name
+}
+
+func (l *CenterLayout) centerProportion() float64 {
+	precision := 1.0 / common.Config.ProportionStep
+	return math.Round(proportions(l.Proportions.Columns, 3)[1]*precision) / precision
+}
+
+func (l *CenterLayout) setCenterProportion(pm float64) {
+	ps := proportions(l.Proportions.Columns, 3)
+
+	// Calculate proportions with centered master
+	pl := ps[0] - (pm-ps[1])/2
+	pr := 1.0 - pm - pl
+
+	// Clamp proportions
+	for _, p := range []float64{pl, pm, pr} {
+		if p != math.Min(math.Max(p, common.Config.ProportionMin), 1.0-common.Config.ProportionMin) {
+			return
+		}
+	}
+
+	// Update proportions
+	ps[0], ps[1], ps[2] = pl, pm, pr
+}
+
+func (l *CenterLayout) columns() []column {
+	dx, _, dw, _ := store.DesktopGeometry(l.Location.Screen).Pieces()
+
+	msize := common.MinInt(len(l.Masters.Stacked), l.Masters.Maximum)
+	ssize := common.MinInt(len(l.Slaves.Stacked), l.Slaves.Maximum)
+
+	// Distribute slaves alternating on the right and left side
+	left, right := []*store.Client{}, []*store.Client{}
+	for i, c := range l.Slaves.Stacked {
+		if (i%ssize)%2 == 0 {
+			right = append(right, c)
+		} else {
+			left = append(left, c)
+		}
+	}
+
+	// Group clients into columns, a single slave leaves the left column empty
+	columns := []column{}
+	if ssize > 1 || (ssize > 0 && msize > 0) {
+		columns = append(columns, column{Rows: ssize / 2, Clients: left})
+	}
+	if msize > 0 {
+		columns = append(columns, column{Rows: msize, Clients: l.Masters.Stacked})
+	}
+	if ssize > 0 {
+		columns = append(columns, column{Rows: ssize - ssize/2, Clients: right})
+	}
+
+	// Calculate column dimensions
+	x := dx
+	for i := range columns {
+		w := dw - (x - dx)
+		if i < len(columns)-1 {
+			w = int(math.Round(float64(dw) * proportions(l.Proportions.Columns, len(columns))[i]))
+		}
+		columns[i].X = x
+		columns[i].Width = w
+		columns[i].Last = i == len(columns)-1
+		x += w
+	}
+
+	return columns
+}
+
+func proportions(ps map[int][]float64, n int) []float64 {
+	if p, ok := ps[n]; ok && len(p) == n {
+		return p
+	}
+
+	// Create equal proportions for unknown sizes
+	p := make([]float64, n)
+	for i := range p {
+		p[i] = 1.0 / float64(n)
+	}
+
+	return p
+}
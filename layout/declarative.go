@@ -0,0 +1,493 @@
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type DeclarativeLayout struct {
+	Name           string  // Layout name
+	*store.Manager         // Layout store manager
+	Nodes          []*Node `json:"-"` // Layout nodes in pre-order
+}
+
+type Node struct {
+	Index    int                // Node index in pre-order
+	Parent   *Node              // Parent node
+	Children []*Node            // Child nodes
+	Config   *common.LayoutNode // Node definition
+}
+
+type slots struct {
+	Masters []*Node                   // Leaf nodes holding masters
+	Slaves  []*Node                   // Leaf nodes holding slaves
+	Counts  map[*Node]int             // Number of clients per leaf node
+	Areas   map[*Node]common.Geometry // Area geometry per node
+}
+
+func CreateDeclarativeLayouts(loc store.Location) []*DeclarativeLayout {
+	layouts := []*DeclarativeLayout{}
+	for i := range common.Config.Layouts {
+		definition := &common.Config.Layouts[i]
+		if len(definition.Name) == 0 {
+			continue
+		}
+
+		// Validate layout definition
+		if err := validateLayout(definition); err != nil {
+			log.Warn("Error creating layout ", definition.Name, ": ", err)
+			continue
+		}
+
+		layouts = append(layouts, CreateDeclarativeLayout(loc, definition))
+	}
+	return layouts
+}
+
+func CreateDeclarativeLayout(loc store.Location, definition *common.LayoutDefinition) *DeclarativeLayout {
+	layout := &DeclarativeLayout{
+		Name:    definition.Name,
+		Manager: store.CreateManager(loc),
+		Nodes:   createNodes(&definition.Root, nil, []*Node{}),
+	}
+	layout.Reset()
+	return layout
+}
+
+func (l *DeclarativeLayout) Reset() {
+	mg := store.CreateManager(*l.Location)
+
+	// Reset number of masters
+	for l.Masters.Maximum < mg.Masters.Maximum {
+		l.IncreaseMaster()
+	}
+	for l.Masters.Maximum > mg.Masters.Maximum {
+		l.DecreaseMaster()
+	}
+
+	// Reset number of slaves
+	for l.Slaves.Maximum < mg.Slaves.Maximum {
+		l.IncreaseSlave()
+	}
+	for l.Slaves.Maximum > mg.Slaves.Maximum {
+		l.DecreaseSlave()
+	}
+
+	// Reset layout proportions
+	l.Manager.Proportions = mg.Proportions
+
+	// Reset split proportions
+	for _, n := range l.Nodes {
+		if len(n.Children) == 0 {
+			continue
+		}
+		ratio := n.Config.Ratio
+		if ratio == 0 {
+			ratio = 0.5
+		}
+		l.Proportions.Splits[n.Index] = []float64{ratio, 1.0 - ratio}
+	}
+}
+
+func (l *DeclarativeLayout) Apply() {
+	clients := l.Clients(store.Stacked)
+
+	_, _, dw, dh := store.DesktopGeometry(l.Location.Screen).Pieces()
+	gap := common.Config.WindowGapSize
+
+	csize := len(clients)
+
+	log.Info("Tile ", csize, " windows with ", l.Name, " layout [workspace-", l.Location.Desktop, "-", l.Location.Screen, "]")
+
+	// Calculate slot dimensions
+	s := l.slots()
+
+	// Master and slave area layout
+	for _, c := range clients {
+		leaf, pos := l.leaf(s, c)
+		if leaf == nil {
+			continue
+		}
+		x, y, w, h := l.cell(s, leaf, pos)
+
+		minpw := common.Config.ProportionMin
+		minph := common.Config.ProportionMin
+
+		// Adjust sizes and proportions
+		if w >= dw-2*gap {
+			minpw = 1.0
+		}
+		if h >= dh-2*gap {
+			minph = 1.0
+		}
+
+		// Limit minimum dimensions
+		minw := common.MinInt(w, int(math.Round(float64(dw-2*gap)*minpw)))
+		minh := common.MinInt(h, int(math.Round(float64(dh-2*gap)*minph)))
+		c.Limit(minw, minh)
+
+		// Move and resize client
+		c.MoveWindow(x, y, w, h)
+	}
+}
+
+func (l *DeclarativeLayout) UpdateProportions(c *store.Client, g *common.Geometry, d *store.Directions) {
+	cx, cy, cw, ch := g.Pieces()
+
+	gap := common.Config.WindowGapSize
+
+	// Obtain slot of client
+	s := l.slots()
+	leaf, pos := l.leaf(s, c)
+	if leaf == nil {
+		return
+	}
+	area := s.Areas[leaf]
+	count := s.Counts[leaf]
+	ps := l.stackProportions(leaf, count)
+	vertical := leaf.Config.Direction == "vertical"
+
+	// Calculate cell boundaries
+	start, end := 0, 0
+	for i := 0; i <= pos; i++ {
+		start = end
+		end = cellOffset(area, vertical, ps, i+1)
+	}
+
+	// Set stacking proportions
+	dir := *d
+	if vertical {
+		if dir.Left && pos > 0 {
+			l.Manager.SetProportions(ps, float64(area.X+end-(cx-gap))/float64(area.Width), pos, pos-1)
+			dir.Left = false
+		} else if dir.Right && pos < count-1 {
+			l.Manager.SetProportions(ps, float64(cx+cw-(area.X+start))/float64(area.Width), pos, pos+1)
+			dir.Right = false
+		}
+	} else {
+		if dir.Top && pos > 0 {
+			l.Manager.SetProportions(ps, float64(area.Y+end-(cy-gap))/float64(area.Height), pos, pos-1)
+			dir.Top = false
+		} else if dir.Bottom && pos < count-1 {
+			l.Manager.SetProportions(ps, float64(cy+ch-(area.Y+start))/float64(area.Height), pos, pos+1)
+			dir.Bottom = false
+		}
+	}
+
+	// Set split proportions
+	for child, n := leaf, leaf.Parent; n != nil; child, n = n, n.Parent {
+		first := n.Children[0] == child
+		a := s.Areas[n]
+
+		// Ignore collapsed splits
+		if _, ok := s.Areas[n.Children[0]]; !ok {
+			continue
+		}
+		if _, ok := s.Areas[n.Children[1]]; !ok {
+			continue
+		}
+
+		// Update nearest split in edge direction
+		if n.Config.Direction == "vertical" {
+			if dir.Left && !first {
+				l.Manager.SetProportions(l.Proportions.Splits[n.Index], float64(cx-gap-a.X)/float64(a.Width), 0, 1)
+				dir.Left = false
+			} else if dir.Right && first {
+				l.Manager.SetProportions(l.Proportions.Splits[n.Index], float64(cx+cw-a.X)/float64(a.Width), 0, 1)
+				dir.Right = false
+			}
+		} else {
+			if dir.Top && !first {
+				l.Manager.SetProportions(l.Proportions.Splits[n.Index], float64(cy-gap-a.Y)/float64(a.Height), 0, 1)
+				dir.Top = false
+			} else if dir.Bottom && first {
+				l.Manager.SetProportions(l.Proportions.Splits[n.Index], float64(cy+ch-a.Y)/float64(a.Height), 0, 1)
+				dir.Bottom = false
+			}
+		}
+	}
+}
+
+func (l *DeclarativeLayout) IncreaseProportion() {
+	precision := 1.0 / common.Config.ProportionStep
+	proportion := math.Round(l.Proportions.Splits[0][0]*precision)/precision + common.Config.ProportionStep
+
+	// Increase root proportion
+	l.Manager.SetProportions(l.Proportions.Splits[0], proportion, 0, 1)
+}
+
+func (l *DeclarativeLayout) DecreaseProportion() {
+	precision := 1.0 / common.Config.ProportionStep
+	proportion := math.Round(l.Proportions.Splits[0][0]*precision)/precision - common.Config.ProportionStep
+
+	// Decrease root proportion
+	l.Manager.SetProportions(l.Proportions.Splits[0], proportion, 0, 1)
+}
+
+func (l *DeclarativeLayout) SetProportion(p float64) {
+
+	// Set root proportion
+	l.Manager.SetProportions(l.Proportions.Splits[0], p, 0, 1)
+}
+
+func (l *DeclarativeLayout) GetProportions() map[string][]float64 {
+	s := l.slots()
+
+	// Splits in use for the current client counts
+	n := 0
+	for _, node := range l.Nodes {
+		if len(node.Children) == 0 {
+			continue
+		}
+		if l.hasClients(s, node.Children[0]) && l.hasClients(s, node.Children[1]) {
+			n = node.Index + 1
+		}
+	}
+	ps := map[string][]float64{}
+	if n > 0 {
+		ps["splits"] = splits(l.Proportions.Splits, n)
+	}
+
+	return ps
+}
+
+func (l *DeclarativeLayout) GetManager() *store.Manager {
+	return l.Manager
+}
+
+func (l *DeclarativeLayout) GetName() string {
+	return l.Name
+}
+
+func (l *DeclarativeLayout) slots() *slots {
+	s := &slots{
+		Counts: map[*Node]int{},
+		Areas:  map[*Node]common.Geometry{},
+	}
+
+	// Obtain leaf nodes
+	for _, n := range l.Nodes {
+		switch n.Config.Slot {
+		case "masters":
+			s.Masters = append(s.Masters, n)
+		case "slaves":
+			s.Slaves = append(s.Slaves, n)
+		}
+	}
+
+	// Distribute clients round-robin over leaf nodes
+	msize := common.MinInt(len(l.Masters.Stacked), l.Masters.Maximum)
+	ssize := common.MinInt(len(l.Slaves.Stacked), l.Slaves.Maximum)
+	for i := 0; i < msize; i++ {
+		s.Counts[s.Masters[i%len(s.Masters)]]++
+	}
+	for i := 0; i < ssize; i++ {
+		s.Counts[s.Slaves[i%len(s.Slaves)]]++
+	}
+
+	// Calculate node areas
+	dx, dy, dw, dh := store.DesktopGeometry(l.Location.Screen).Pieces()
+	l.area(s, l.Nodes[0], common.Geometry{X: dx, Y: dy, Width: dw, Height: dh})
+
+	return s
+}
+
+func (l *DeclarativeLayout) area(s *slots, n *Node, a common.Geometry) bool {
+
+	// Leaf nodes are used if they contain clients
+	if len(n.Children) == 0 {
+		if s.Counts[n] == 0 {
+			return false
+		}
+		s.Areas[n] = a
+		return true
+	}
+
+	// Split nodes collapse if a child is empty
+	p := l.Proportions.Splits[n.Index][0]
+	first, second := a, a
+	if n.Config.Direction == "vertical" {
+		first.Width = int(math.Round(float64(a.Width) * p))
+		second.X, second.Width = a.X+first.Width, a.Width-first.Width
+	} else {
+		first.Height = int(math.Round(float64(a.Height) * p))
+		second.Y, second.Height = a.Y+first.Height, a.Height-first.Height
+	}
+	if !l.hasClients(s, n.Children[1]) {
+		first = a
+	}
+	if !l.hasClients(s, n.Children[0]) {
+		second = a
+	}
+	used := l.area(s, n.Children[0], first)
+	used = l.area(s, n.Children[1], second) || used
+	if used {
+		s.Areas[n] = a
+	}
+
+	return used
+}
+
+func (l *DeclarativeLayout) hasClients(s *slots, n *Node) bool {
+	if len(n.Children) == 0 {
+		return s.Counts[n] > 0
+	}
+	return l.hasClients(s, n.Children[0]) || l.hasClients(s, n.Children[1])
+}
+
+func (l *DeclarativeLayout) leaf(s *slots, c *store.Client) (*Node, int) {
+	leaves := s.Slaves
+	windows := l.Slaves
+	if l.IsMaster(c) {
+		leaves = s.Masters
+		windows = l.Masters
+	}
+
+	// Obtain visible index of client
+	size := common.MinInt(len(windows.Stacked), windows.Maximum)
+	i := l.Index(windows, c)
+	if i < 0 || size == 0 {
+		return nil, 0
+	}
+	k := i % size
+
+	return leaves[k%len(leaves)], k / len(leaves)
+}
+
+func (l *DeclarativeLayout) cell(s *slots, leaf *Node, pos int) (int, int, int, int) {
+	dx, dy, dw, dh := store.DesktopGeometry(l.Location.Screen).Pieces()
+	gap := common.Config.WindowGapSize
+
+	area := s.Areas[leaf]
+	ps := l.stackProportions(leaf, s.Counts[leaf])
+	vertical := leaf.Config.Direction == "vertical"
+
+	// Calculate cell area
+	start := cellOffset(area, vertical, ps, pos)
+	end := cellOffset(area, vertical, ps, pos+1)
+	x, y, w, h := area.X, area.Y, area.Width, area.Height
+	if vertical {
+		x, w = area.X+start, end-start
+	} else {
+		y, h = area.Y+start, end-start
+	}
+
+	// Add gaps between cells and on desktop borders
+	if x+w >= dx+dw {
+		w -= gap
+	}
+	if y+h >= dy+dh {
+		h -= gap
+	}
+	w -= gap
+	h -= gap
+
+	return x + gap, y + gap, w, h
+}
+
+func (l *DeclarativeLayout) stackProportions(leaf *Node, n int) []float64 {
+	if leaf.Config.Slot == "masters" {
+		return l.Proportions.MasterMaster[n]
+	}
+	return l.Proportions.SlaveSlave[n]
+}
+
+func cellOffset(area common.Geometry, vertical bool, ps []float64, pos int) int {
+	size := area.Height
+	if vertical {
+		size = area.Width
+	}
+
+	// Last cell fills the remaining area
+	if pos >= len(ps) {
+		return size
+	}
+
+	offset := 0.0
+	for i := 0; i < pos; i++ {
+		offset += ps[i]
+	}
+
+	return int(math.Round(float64(size) * offset))
+}
+
+func createNodes(config *common.LayoutNode, parent *Node, nodes []*Node) []*Node {
+	n := &Node{
+		Index:  len(nodes),
+		Parent: parent,
+		Config: config,
+	}
+	nodes = append(nodes, n)
+
+	// Create child nodes in pre-order
+	for i := range config.Children {
+		child := len(nodes)
+		nodes = createNodes(&config.Children[i], n, nodes)
+		n.Children = append(n.Children, nodes[child])
+	}
+
+	return nodes
+}
+
+func validateLayout(definition *common.LayoutDefinition) error {
+	// Validate layout nodes
+	masters, slaves, err := validateNode(&definition.Root)
+	if err != nil {
+		return err
+	}
+	if masters == 0 || slaves == 0 {
+		return errors.New("layout requires at least one masters and one slaves slot")
+	}
+
+	return nil
+}
+
+func validateNode(n *common.LayoutNode) (int, int, error) {
+	if !common.IsInList(n.Direction, []string{"", "vertical", "horizontal"}) {
+		return 0, 0, fmt.Errorf("invalid direction %q", n.Direction)
+	}
+
+	// Validate leaf nodes
+	if len(n.Children) == 0 {
+		switch n.Slot {
+		case "masters":
+			return 1, 0, nil
+		case "slaves":
+			return 0, 1, nil
+		}
+		return 0, 0, fmt.Errorf("invalid slot %q", n.Slot)
+	}
+
+	// Validate split nodes
+	if len(n.Children) != 2 {
+		return 0, 0, fmt.Errorf("split requires exactly two children, got %d", len(n.Children))
+	}
+	if len(n.Slot) > 0 {
+		return 0, 0, fmt.Errorf("split can not hold slot %q", n.Slot)
+	}
+	if len(n.Direction) == 0 {
+		return 0, 0, errors.New("split requires a direction")
+	}
+	if n.Ratio < 0 || n.Ratio >= 1 {
+		return 0, 0, fmt.Errorf("invalid ratio %v", n.Ratio)
+	}
+
+	// Validate child nodes
+	masters, slaves := 0, 0
+	for i := range n.Children {
+		m, s, err := validateNode(&n.Children[i])
+		if err != nil {
+			return 0, 0, err
+		}
+		masters += m
+		slaves += s
+	}
+
+	return masters, slaves, nil
+}
@@ -0,0 +1,262 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type DwindleLayout struct {
+	Name           string // Layout name
+	*store.Manager        // Layout store manager
+}
+
+func CreateDwindleLayout(loc store.Location) *DwindleLayout {
+	layout := &DwindleLayout{
+		Name:    "dwindle",
+		Manager: store.CreateManager(loc),
+	}
+	layout.Reset()
+	return layout
+}
+
+func (l *DwindleLayout) Reset() {
+	mg := store.CreateManager(*l.Location)
+
+	// Reset number of masters
+	for l.Masters.Maximum < mg.Masters.Maximum {
+		l.IncreaseMaster()
+	}
+	for l.Masters.Maximum > mg.Masters.Maximum {
+		l.DecreaseMaster()
+	}
+
+	// Reset number of slaves
+	for l.Slaves.Maximum < mg.Slaves.Maximum {
+		l.IncreaseSlave()
+	}
+	for l.Slaves.Maximum > mg.Slaves.Maximum {
+		l.DecreaseSlave()
+	}
+
+	// Reset layout proportions
+	l.Manager.Proportions = mg.Proportions
+}
+
+func (l *DwindleLayout) Apply() {
+	clients := l.Clients(store.Stacked)
+
+	_, _, dw, dh := store.DesktopGeometry(l.Location.Screen).Pieces()
+	gap := common.Config.WindowGapSize
+
+	msize := common.MinInt(len(l.Masters.Stacked), l.Masters.Maximum)
+	ssize := common.MinInt(len(l.Slaves.Stacked), l.Slaves.Maximum)
+	csize := len(clients)
+
+	log.Info("Tile ", csize, " windows with ", l.Name, " layout [workspace-", l.Location.Desktop, "-", l.Location.Screen, "]")
+
+	// Calculate tile dimensions
+	tiles, _ := l.tiles(msize + ssize)
+
+	minpw := common.Config.ProportionMin
+	minph := common.Config.ProportionMin
+
+	// Adjust sizes and proportions
+	if len(tiles) == 1 {
+		minpw = 1.0
+		minph = 1.0
+	}
+
+	// Master area layout
+	for i, c := range l.Masters.Stacked {
+		if msize == 0 {
+			break
+		}
+		tile := tiles[i%msize]
+
+		// Limit minimum dimensions
+		minw := common.MinInt(tile.Width, int(math.Round(float64(dw-2*gap)*minpw)))
+		minh := common.MinInt(tile.Height, int(math.Round(float64(dh-2*gap)*minph)))
+		c.Limit(minw, minh)
+
+		// Move and resize master
+		c.MoveWindow(tile.Pieces())
+	}
+
+	// Slave area layout
+	for i, c := range l.Slaves.Stacked {
+		if ssize == 0 {
+			break
+		}
+		tile := tiles[msize+i%ssize]
+
+		// Limit minimum dimensions
+		minw := common.MinInt(tile.Width, int(math.Round(float64(dw-2*gap)*minpw)))
+		minh := common.MinInt(tile.Height, int(math.Round(float64(dh-2*gap)*minph)))
+		c.Limit(minw, minh)
+
+		// Move and resize slave
+		c.MoveWindow(tile.Pieces())
+	}
+}
+
+func (l *DwindleLayout) UpdateProportions(c *store.Client, g *common.Geometry, d *store.Directions) {
+	cx, cy, cw, ch := g.Pieces()
+
+	gap := common.Config.WindowGapSize
+
+	msize := common.MinInt(len(l.Masters.Stacked), l.Masters.Maximum)
+	ssize := common.MinInt(len(l.Slaves.Stacked), l.Slaves.Maximum)
+
+	// Obtain tile index of client
+	idx := -1
+	if l.IsMaster(c) && msize > 0 {
+		idx = l.Index(l.Masters, c) % msize
+	} else if l.IsSlave(c) && ssize > 0 {
+		idx = msize + l.Index(l.Slaves, c)%ssize
+	}
+	if idx < 0 {
+		return
+	}
+
+	// Obtain split areas
+	_, areas := l.tiles(msize + ssize)
+
+	// Set proportions of own split (right or bottom edge)
+	if idx < len(areas) {
+		area := areas[idx]
+		if idx%2 == 0 && d.Right {
+			px := float64(cw) / float64(area.Width-gap)
+			l.Manager.SetProportions(l.Proportions.Splits[idx], px, 0, 1)
+		} else if idx%2 == 1 && d.Bottom {
+			py := float64(ch) / float64(area.Height-gap)
+			l.Manager.SetProportions(l.Proportions.Splits[idx], py, 0, 1)
+		}
+	}
+
+	// Set proportions of parent split (left edge)
+	if d.Left {
+		for i := idx - 1; i >= 0; i-- {
+			if i%2 == 0 {
+				area := areas[i]
+				px := float64(cx-area.X-gap) / float64(area.Width-gap)
+				l.Manager.SetProportions(l.Proportions.Splits[i], px, 0, 1)
+				break
+			}
+		}
+	}
+
+	// Set proportions of parent split (top edge)
+	if d.Top {
+		for i := idx - 1; i >= 0; i-- {
+			if i%2 == 1 {
+				area := areas[i]
+				py := float64(cy-area.Y-gap) / float64(area.Height-gap)
+				l.Manager.SetProportions(l.Proportions.Splits[i], py, 0, 1)
+				break
+			}
+		}
+	}
+}
+
+func (l *DwindleLayout) IncreaseProportion() {
+	precision := 1.0 / common.Config.ProportionStep
+	proportion := math.Round(l.Proportions.Splits[0][0]*precision)/precision + common.Config.ProportionStep
+
+	// Increase root proportion
+	l.Manager.SetProportions(l.Proportions.Splits[0], proportion, 0, 1)
+}
+
+func (l *DwindleLayout) DecreaseProportion() {
+	precision := 1.0 / common.Config.ProportionStep
+	proportion := math.Round(l.Proportions.Splits[0][0]*precision)/precision - common.Config.ProportionStep
+
+	// Decrease root proportion
+	l.Manager.SetProportions(l.Proportions.Splits[0], proportion, 0, 1)
+}
+
+func (l *DwindleLayout) SetProportion(p float64) {
+
+	// Set root proportion
+	l.Manager.SetProportions(l.Proportions.Splits[0], p, 0, 1)
+}
+
+func (l *DwindleLayout) GetProportions() map[string][]float64 {
+	msize := common.MinInt(len(l.Masters.Stacked), l.Masters.Maximum)
+	ssize := common.MinInt(len(l.Slaves.Stacked), l.Slaves.Maximum)
+
+	// Splits in use for the current client counts
+	ps := map[string][]float64{}
+	if n := msize + ssize - 1; n > 0 {
+		ps["splits"] = splits(l.Proportions.Splits, n)
+	}
+
+	return ps
+}
+
+func (l *DwindleLayout) GetManager() *store.Manager {
+	return l.Manager
+}
+
+func (l *DwindleLayout) GetName() string {
+	return l.Name
+}
+
+func (l *DwindleLayout) tiles(n int) ([]common.Geometry, []common.Geometry) {
+	dx, dy, dw, dh := store.DesktopGeometry(l.Location.Screen).Pieces()
+	gap := common.Config.WindowGapSize
+
+	tiles := []common.Geometry{}
+	areas := []common.Geometry{}
+
+	// Remaining area without outer gaps
+	x, y, w, h := dx+gap, dy+gap, dw-2*gap, dh-2*gap
+
+	for i := 0; i < n; i++ {
+
+		// Last tile fills the remaining area
+		if i == n-1 {
+			tiles = append(tiles, common.Geometry{X: x, Y: y, Width: w, Height: h})
+			break
+		}
+		areas = append(areas, common.Geometry{X: x, Y: y, Width: w, Height: h})
+
+		// Obtain split proportion
+		p := 0.5
+		if ps, ok := l.Proportions.Splits[i]; ok {
+			p = ps[0]
+		}
+
+		// Split remaining area alternating vertically and horizontally
+		if i%2 == 0 {
+			tw := int(math.Round(float64(w-gap) * p))
+			tiles = append(tiles, common.Geometry{X: x, Y: y, Width: tw, Height: h})
+			x += tw + gap
+			w -= tw + gap
+		} else {
+			th := int(math.Round(float64(h-gap) * p))
+			tiles = append(tiles, common.Geometry{X: x, Y: y, Width: w, Height: th})
+			y += th + gap
+			h -= th + gap
+		}
+	}
+
+	return tiles, areas
+}
+
+func splits(ps map[int][]float64, n int) []float64 {
+	p := make([]float64, n)
+
+	// Obtain first child proportion of each split
+	for i := range p {
+		p[i] = 0.5
+		if s, ok := ps[i]; ok {
+			p[i] = s[0]
+		}
+	}
+
+	return p
+}
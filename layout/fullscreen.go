@@ -3,6 +3,7 @@ package layout
 import (
 	"math"
 
+	"github.com/leukipp/cortile/v2/common"
 	"github.com/leukipp/cortile/v2/store"
 
 	log "github.com/sirupsen/logrus"
@@ -52,10 +53,16 @@ func (l *FullscreenLayout) Apply() {
 	}
 }
 
-func (l *FullscreenLayout) UpdateProportions(c *store.Client, d *store.Directions) {
+func (l *FullscreenLayout) UpdateProportions(c *store.Client, g *common.Geometry, d *store.Directions) {
 	l.Reset()
 }
 
+func (l *FullscreenLayout) GetProportions() map[string][]float64 {
+
+	// Layout does not use proportions
+	return map[string][]float64{}
+}
+
 func (l *FullscreenLayout) GetManager() *store.Manager {
 	return l.Manager
 }
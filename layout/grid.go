@@ -0,0 +1,226 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type GridLayout struct {
+	Name           string // Layout name
+	*store.Manager        // Layout store manager
+}
+
+func CreateGridLayout(loc store.Location) *GridLayout {
+	layout := &GridLayout{
+		Name:    "grid",
+		Manager: store.CreateManager(loc),
+	}
+	layout.Reset()
+	return layout
+}
+
+func (l *GridLayout) Reset() {
+	mg := store.CreateManager(*l.Location)
+
+	// Reset number of masters
+	for l.Masters.Maximum < mg.Masters.Maximum {
+		l.IncreaseMaster()
+	}
+	for l.Masters.Maximum > mg.Masters.Maximum {
+		l.DecreaseMaster()
+	}
+
+	// Reset number of slaves
+	for l.Slaves.Maximum < mg.Slaves.Maximum {
+		l.IncreaseSlave()
+	}
+	for l.Slaves.Maximum > mg.Slaves.Maximum {
+		l.DecreaseSlave()
+	}
+
+	// Reset layout proportions
+	l.Manager.Proportions = mg.Proportions
+}
+
+func (l *GridLayout) Apply() {
+	clients := l.Clients(store.Stacked)
+
+	dx, dy, dw, dh := store.DesktopGeometry(l.Location.Screen).Pieces()
+	gap := common.Config.WindowGapSize
+
+	csize := len(clients)
+
+	log.Info("Tile ", csize, " windows with ", l.Name, " layout [workspace-", l.Location.Desktop, "-", l.Location.Screen, "]")
+
+	// Grid area layout
+	for i, c := range clients {
+		col, row, cols, rows := l.cell(i, csize)
+
+		minpw := common.Config.ProportionMin
+		minph := common.Config.ProportionMin
+
+		// Adjust sizes and proportions
+		if cols == 1 {
+			minpw = 1.0
+		}
+		if rows == 1 {
+			minph = 1.0
+		}
+
+		// Calculate x position and width
+		cx := dx + gap
+		cw := 0
+		for j := 0; j <= col; j++ {
+			cx += cw
+			if j > 0 {
+				cx += gap
+			}
+			cw = int(math.Round(float64(dw-(cols+1)*gap) * proportions(l.Proportions.Columns, cols)[j]))
+		}
+
+		// Calculate y position and height
+		cy := dy + gap
+		ch := 0
+		for j := 0; j <= row; j++ {
+			cy += ch
+			if j > 0 {
+				cy += gap
+			}
+			ch = int(math.Round(float64(dh-(rows+1)*gap) * proportions(l.Proportions.Rows, rows)[j]))
+		}
+
+		// Limit minimum dimensions
+		minw := int(math.Round(float64(dw-(cols+1)*gap) * minpw))
+		minh := int(math.Round(float64(dh-(rows+1)*gap) * minph))
+		c.Limit(minw, minh)
+
+		// Move and resize client
+		c.MoveWindow(cx, cy, cw, ch)
+	}
+}
+
+func (l *GridLayout) UpdateProportions(c *store.Client, g *common.Geometry, d *store.Directions) {
+	_, _, dw, dh := store.DesktopGeometry(l.Location.Screen).Pieces()
+	_, _, cw, ch := g.Pieces()
+
+	gap := common.Config.WindowGapSize
+
+	// Obtain grid cell of client
+	clients := l.Clients(store.Stacked)
+	idx := -1
+	for i, cc := range clients {
+		if cc.Window.Id == c.Window.Id {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return
+	}
+	col, row, cols, rows := l.cell(idx, len(clients))
+
+	// Calculate proportions based on window geometry
+	px := float64(cw) / float64(dw-(cols+1)*gap)
+	py := float64(ch) / float64(dh-(rows+1)*gap)
+
+	// Set column proportions
+	if d.Left {
+		l.Manager.SetProportions(proportions(l.Proportions.Columns, cols), px, col, col-1)
+	} else if d.Right {
+		l.Manager.SetProportions(proportions(l.Proportions.Columns, cols), px, col, col+1)
+	}
+
+	// Set row proportions
+	if d.Top {
+		l.Manager.SetProportions(proportions(l.Proportions.Rows, rows), py, row, row-1)
+	} else if d.Bottom {
+		l.Manager.SetProportions(proportions(l.Proportions.Rows, rows), py, row, row+1)
+	}
+}
+
+func (l *GridLayout) IncreaseProportion() {
+
+	// Increase first column and row proportion
+	l.stepProportions(common.Config.ProportionStep)
+}
+
+func (l *GridLayout) DecreaseProportion() {
+
+	// Decrease first column and row proportion
+	l.stepProportions(-common.Config.ProportionStep)
+}
+
+func (l *GridLayout) SetProportion(p float64) {
+	cols, rows := l.size()
+
+	// Set first column and row proportion
+	l.Manager.SetProportions(proportions(l.Proportions.Columns, cols), p, 0, 1)
+	l.Manager.SetProportions(proportions(l.Proportions.Rows, rows), p, 0, 1)
+}
+
+func (l *GridLayout) GetProportions() map[string][]float64 {
+	cols, rows := l.size()
+
+	// Proportions in use for the current client counts
+	ps := map[string][]float64{}
+	if cols > 1 {
+		ps["columns"] = proportions(l.Proportions.Columns, cols)
+	}
+	if rows > 1 {
+		ps["rows"] = proportions(l.Proportions.Rows, rows)
+	}
+
+	return ps
+}
+
+func (l *GridLayout) GetManager() *store.Manager {
+	return l.Manager
+}
+
+func (l *GridLayout) GetName() string {
+	return l.Name
+}
+
+func (l *GridLayout) cell(i int, n int) (int, int, int, int) {
+	cols := int(math.Ceil(math.Sqrt(float64(n))))
+	rows := int(math.Ceil(float64(n) / float64(cols)))
+
+	// Obtain cell position
+	col := i % cols
+	row := i / cols
+
+	// Last row may contain fewer columns
+	if row == rows-1 {
+		cols = n - (rows-1)*cols
+	}
+
+	return col, row, cols, rows
+}
+
+func (l *GridLayout) size() (int, int) {
+	n := len(l.Clients(store.Stacked))
+	if n == 0 {
+		return 0, 0
+	}
+
+	// Obtain columns and rows of first grid row
+	_, _, cols, rows := l.cell(0, n)
+
+	return cols, rows
+}
+
+func (l *GridLayout) stepProportions(step float64) {
+	precision := 1.0 / common.Config.ProportionStep
+	cols, rows := l.size()
+
+	// Step proportions of first column and row
+	for _, ps := range [][]float64{proportions(l.Proportions.Columns, cols), proportions(l.Proportions.Rows, rows)} {
+		if len(ps) < 2 {
+			continue
+		}
+		l.Manager.SetProportions(ps, math.Round(ps[0]*precision)/precision+step, 0, 1)
+	}
+}
@@ -164,9 +164,9 @@ func (l *HorizontalLayout) Apply() {
 	}
 }
 
-func (l *HorizontalLayout) UpdateProportions(c *store.Client, d *store.Directions) {
+func (l *HorizontalLayout) UpdateProportions(c *store.Client, g *common.Geometry, d *store.Directions) {
 	_, _, dw, dh := store.DesktopGeometry(l.Location.Screen).Pieces()
-	_, _, cw, ch := c.OuterGeometry()
+	_, _, cw, ch := g.Pieces()
 
 	gap := common.Config.WindowGapSize
 
@@ -227,6 +227,25 @@ func (l *HorizontalLayout) UpdateProportions(c *store.Client, d *store.Direction
 	}
 }
 
+func (l *HorizontalLayout) GetProportions() map[string][]float64 {
+	msize := common.MinInt(len(l.Masters.Stacked), l.Masters.Maximum)
+	ssize := common.MinInt(len(l.Slaves.Stacked), l.Slaves.Maximum)
+
+	// Proportions in use for the current client counts
+	ps := map[string][]float64{}
+	if msize > 0 && ssize > 0 {
+		ps["master_slave"] = l.Proportions.MasterSlave[2]
+	}
+	if msize > 1 {
+		ps["master_master"] = l.Proportions.MasterMaster[msize]
+	}
+	if ssize > 1 {
+		ps["slave_slave"] = l.Proportions.SlaveSlave[ssize]
+	}
+
+	return ps
+}
+
 func (l *HorizontalLayout) GetManager() *store.Manager {
 	return l.Manager
 }
@@ -53,10 +53,16 @@ func (l *MaximizedLayout) Apply() {
 	}
 }
 
-func (l *MaximizedLayout) UpdateProportions(c *store.Client, d *store.Directions) {
+func (l *MaximizedLayout) UpdateProportions(c *store.Client, g *common.Geometry, d *store.Directions) {
 	l.Reset()
 }
 
+func (l *MaximizedLayout) GetProportions() map[string][]float64 {
+
+	// Layout does not use proportions
+	return map[string][]float64{}
+}
+
 func (l *MaximizedLayout) GetManager() *store.Manager {
 	return l.Manager
 }
@@ -0,0 +1,104 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type TabbedLayout struct {
+	Name           string // Layout name
+	*store.Manager        // Layout store manager
+}
+
+func CreateTabbedLayout(loc store.Location) *TabbedLayout {
+	layout := &TabbedLayout{
+		Name:    "tabbed",
+		Manager: store.CreateManager(loc),
+	}
+	layout.Reset()
+	return layout
+}
+
+func (l *TabbedLayout) Reset() {
+	mg := store.CreateManager(*l.Location)
+
+	// Reset number of masters
+	for l.Masters.Maximum < mg.Masters.Maximum {
+		l.IncreaseMaster()
+	}
+	for l.Masters.Maximum > mg.Masters.Maximum {
+		l.DecreaseMaster()
+	}
+
+	// Reset number of slaves
+	for l.Slaves.Maximum < mg.Slaves.Maximum {
+		l.IncreaseSlave()
+	}
+	for l.Slaves.Maximum > mg.Slaves.Maximum {
+		l.DecreaseSlave()
+	}
+
+	// Reset layout proportions
+	l.Manager.Proportions = mg.Proportions
+}
+
+func (l *TabbedLayout) Apply() {
+	clients := l.Clients(store.Stacked)
+
+	dx, dy, dw, dh := store.DesktopGeometry(l.Location.Screen).Pieces()
+	gap := common.Config.WindowGapSize
+
+	csize := len(clients)
+	th := l.tabsHeight()
+
+	log.Info("Tile ", csize, " windows with ", l.Name, " layout [workspace-", l.Location.Desktop, "-", l.Location.Screen, "]")
+
+	// Main area layout below tab bar
+	for _, c := range clients {
+
+		// Limit minimum dimensions
+		minw := int(math.Round(float64(dw - 2*gap)))
+		minh := int(math.Round(float64(dh - 2*gap - th)))
+		c.Limit(minw, minh)
+
+		// Move and resize client
+		c.MoveWindow(dx+gap, dy+gap+th, dw-2*gap, dh-2*gap-th)
+	}
+}
+
+func (l *TabbedLayout) UpdateProportions(c *store.Client, g *common.Geometry, d *store.Directions) {
+}
+
+func (l *TabbedLayout) TabBar() common.Geometry {
+	dx, dy, dw, _ := store.DesktopGeometry(l.Location.Screen).Pieces()
+	gap := common.Config.WindowGapSize
+
+	return common.Geometry{X: dx + gap, Y: dy + gap, Width: dw - 2*gap, Height: common.Config.TilingTabs}
+}
+
+func (l *TabbedLayout) GetProportions() map[string][]float64 {
+
+	// Layout does not use proportions
+	return map[string][]float64{}
+}
+
+func (l *TabbedLayout) GetManager() *store.Manager {
+	return l.Manager
+}
+
+func (l *TabbedLayout) GetName() string {
+	return l.Name
+}
+
+func (l *TabbedLayout) tabsHeight() int {
+	if common.Config.TilingTabs <= 0 {
+		return 0
+	}
+
+	// Tab bar height including gap
+	return common.Config.TilingTabs + common.Config.WindowGapSize
+}
@@ -58,7 +58,7 @@ func main() {
 	common.InitInfo(name, target, version, commit, date, source, flags)
 
 	// Init command line arguments
-	common.InitArgs(input.Introspect())
+	common.InitArgs(input.Introspect, input.IpcIntrospect)
 
 	// Init embedded files
 	common.InitFiles(toml, logo)
@@ -66,6 +66,12 @@ func main() {
 	// Run dbus instance
 	runDbus()
 
+	// Run ipc instance
+	runIpc()
+
+	// Run tree instance
+	runTree()
+
 	// Run main instance
 	runMain()
 }
@@ -97,6 +103,45 @@ func runDbus() {
 	}
 }
 
+func runIpc() {
+	property := len(common.Args.Ipc.Property) > 0
+	method := len(common.Args.Ipc.Method) > 0
+	listen := common.Args.Ipc.Listen
+
+	// Receive ipc property
+	if property {
+		input.IpcProperty(common.Args.Ipc.Property)
+	}
+
+	// Execute ipc method
+	if method {
+		input.IpcMethod(common.Args.Ipc.Method, common.Args.Ipc.P)
+	}
+
+	// Listen to ipc events
+	if listen {
+		go input.IpcListen(common.Args.Ipc.P)
+		select {}
+	}
+
+	// Prevent main instance start
+	if property || method || listen {
+		os.Exit(0)
+	}
+}
+
+func runTree() {
+	if !common.Args.Tree.Enabled {
+		return
+	}
+
+	// Print tiling tree
+	input.Tree(common.Args.Tree.Desktop, common.Args.Tree.Screen, common.Args.Tree.Class, common.Args.Tree.Json)
+
+	// Prevent main instance start
+	os.Exit(0)
+}
+
 func runMain() {
 	defer func() {
 		if err := recover(); err != nil {
@@ -26,11 +26,15 @@ import (
 )
 
 type Client struct {
-	Window   *XWindow // X window object
-	Original *Info    `json:"-"` // Original client window information
-	Cached   *Info    `json:"-"` // Cached client window information
-	Latest   *Info    // Latest client window information
-	Locked   bool     // Internal client move/resize lock
+	Window     *XWindow     // X window object
+	Original   *Info        `json:"-"` // Original client window information
+	Cached     *Info        `json:"-"` // Cached client window information
+	Latest     *Info        // Latest client window information
+	Locked     bool         // Internal client move/resize lock
+	Floating   bool         // Client is excluded from tiling
+	Scratchpad string       `json:"-"` // Client scratchpad name
+	Rule       *common.Rule `json:"-"` // Matching window rule from config
+	Cmdline    []string     `json:"-"` // Client process command line
 }
 
 type Info struct {
@@ -69,6 +73,7 @@ func CreateClient(w xproto.Window) *Client {
 		Cached:   GetInfo(w),
 		Latest:   GetInfo(w),
 		Locked:   false,
+		Cmdline:  GetCommand(w),
 	}
 
 	// Read client from cache
@@ -86,6 +91,16 @@ func CreateClient(w xproto.Window) *Client {
 	c.Latest.Dimensions.Geometry = c.Cached.Dimensions.Geometry
 	c.Latest.Location.Screen = c.Cached.Location.Screen
 
+	// Match window rules
+	c.Rule = MatchRule(c.Latest)
+	if c.Rule != nil {
+		log.Info("Apply window rule from config [", c.Latest.Class, "]")
+	}
+
+	// Restore floating state of the same window only
+	floating := cached.Floating && cached.Window != nil && cached.Window.Id == c.Window.Id
+	c.Floating = floating || (c.Rule != nil && c.Rule.Float)
+
 	return c
 }
 
@@ -195,6 +210,29 @@ func (c *Client) UnMaximize() bool {
 	return true
 }
 
+func (c *Client) Hide() bool {
+	if IsMinimized(GetInfo(c.Window.Id)) {
+		return false
+	}
+
+	// Hide and iconify window
+	ewmh.WmStateReq(X, c.Window.Id, ewmh.StateAdd, "_NET_WM_STATE_HIDDEN")
+	ewmh.ClientEvent(X, c.Window.Id, "WM_CHANGE_STATE", icccm.StateIconic)
+
+	return true
+}
+
+func (c *Client) UnHide() bool {
+	if !IsMinimized(GetInfo(c.Window.Id)) {
+		return false
+	}
+
+	// Unhide window
+	ewmh.WmStateReq(X, c.Window.Id, ewmh.StateRemove, "_NET_WM_STATE_HIDDEN")
+
+	return true
+}
+
 func (c *Client) MoveToDesktop(desktop uint32) bool {
 	if desktop == ^uint32(0) {
 		ewmh.WmStateReq(X, c.Window.Id, ewmh.StateAdd, "_NET_WM_STATE_STICKY")
@@ -416,6 +454,33 @@ func (c *Client) Cache() common.Cache[*Client] {
 	return cache
 }
 
+func (c *Client) IsFloating() bool {
+	return c.Floating || c.IsScratchpad()
+}
+
+func (c *Client) Command() []string {
+	return c.Cmdline
+}
+
+func GetCommand(w xproto.Window) []string {
+	pid, err := ewmh.WmPidGet(X, w)
+	if err != nil {
+		return []string{}
+	}
+
+	// Read process command line
+	data, err := os.ReadFile(filepath.Join("/proc", fmt.Sprint(pid), "cmdline"))
+	if err != nil || len(data) == 0 {
+		return []string{}
+	}
+
+	return strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+}
+
+func (c *Client) IsScratchpad() bool {
+	return len(c.Scratchpad) > 0
+}
+
 func (c *Client) IsNew() bool {
 	created := time.UnixMilli(c.Window.Created)
 	return time.Since(created) < 1000*time.Millisecond
@@ -502,6 +567,21 @@ func IsIgnored(info *Info) bool {
 	return false
 }
 
+func MatchRule(info *Info) *common.Rule {
+	for _, rule := range common.Config.Rules {
+		if len(rule.Class) == 0 && len(rule.Name) == 0 && len(rule.Type) == 0 {
+			continue
+		}
+
+		// Match window class, name and types
+		if rule.Match(info.Class, info.Name, info.Types) {
+			return &rule
+		}
+	}
+
+	return nil
+}
+
 func IsFullscreen(info *Info) bool {
 	return common.IsInList("_NET_WM_STATE_FULLSCREEN", info.States)
 }
@@ -1,6 +1,8 @@
 package store
 
 import (
+	"time"
+
 	"github.com/leukipp/cortile/v2/common"
 
 	log "github.com/sirupsen/logrus"
@@ -11,6 +13,9 @@ type Corner struct {
 	Active   bool            // Mouse pointer is in this corner
 	Screen   uint            // Screen index the corner is located
 	Geometry common.Geometry // Geometry of the corner section
+	Entered  time.Time       `json:"-"` // Time the pointer entered the corner
+	Fired    time.Time       `json:"-"` // Time the corner action was last fired
+	Pushed   time.Time       `json:"-"` // Time the pointer was first pushed against the edge
 }
 
 func CreateCorner(name string, screen uint, x int, y int, w int, h int) *Corner {
@@ -61,6 +66,37 @@ func (c *Corner) IsActive(p *XPointer) bool {
 	return c.Active
 }
 
+func (c *Corner) IsPushed(p *XPointer) bool {
+	if int(c.Screen) >= len(Workplace.Displays.Screens) {
+		return false
+	}
+	x, y, w, h := Workplace.Displays.Screens[c.Screen].Geometry.Pieces()
+
+	// Check if pointer is on screen border
+	return p.Position.X <= x || p.Position.X >= x+w-1 || p.Position.Y <= y || p.Position.Y >= y+h-1
+}
+
+func (c *Corner) IsReady() bool {
+	now := time.Now()
+
+	// Wait for dwell time in corner and push time against edge
+	delay := time.Duration(common.Config.EdgeCornerDelay) * time.Millisecond
+	push := time.Duration(common.Config.EdgeCornerPush) * time.Millisecond
+	if now.Sub(c.Entered) < delay || (push > 0 && (c.Pushed.IsZero() || now.Sub(c.Pushed) < push)) {
+		return false
+	}
+
+	// Fire once or repeat while held
+	repeat := time.Duration(common.Config.EdgeCornerRepeat) * time.Millisecond
+	return c.Fired.IsZero() || (repeat > 0 && now.Sub(c.Fired) >= repeat)
+}
+
+func (c *Corner) Reset() {
+	c.Entered = time.Time{}
+	c.Fired = time.Time{}
+	c.Pushed = time.Time{}
+}
+
 func HotCorner() *Corner {
 
 	// Update active states
@@ -70,15 +106,33 @@ func HotCorner() *Corner {
 		wasActive := hc.Active
 		isActive := hc.IsActive(Pointer)
 
-		// Corner is hot
+		// Corner is entered
 		if !wasActive && isActive {
-			log.Debug("Corner at position ", hc.Geometry, " is hot [", hc.Name, "]")
-			return hc
+			hc.Reset()
+			hc.Entered = time.Now()
 		}
 
 		// Corner was hot
 		if wasActive && !isActive {
 			log.Debug("Corner at position ", hc.Geometry, " is cold [", hc.Name, "]")
+			hc.Reset()
+		}
+		if !isActive {
+			continue
+		}
+
+		// Track time the pointer is pushed against the edge
+		if !hc.IsPushed(Pointer) {
+			hc.Pushed = time.Time{}
+		} else if hc.Pushed.IsZero() {
+			hc.Pushed = time.Now()
+		}
+
+		// Corner is hot (ignored while buttons are pressed)
+		if hc.IsReady() && !Pointer.Pressed() {
+			log.Debug("Corner at position ", hc.Geometry, " is hot [", hc.Name, "]")
+			hc.Fired = time.Now()
+			return hc
 		}
 	}
 
@@ -27,6 +27,9 @@ type Proportions struct {
 	MasterSlave  map[int][]float64 // Master-slave proportions
 	MasterMaster map[int][]float64 // Master-master proportions
 	SlaveSlave   map[int][]float64 // Slave-slave proportions
+	Splits       map[int][]float64 // Binary split proportions
+	Columns      map[int][]float64 // Column proportions
+	Rows         map[int][]float64 // Grid row proportions
 }
 
 type Clients struct {
@@ -55,6 +58,9 @@ func CreateManager(loc Location) *Manager {
 			MasterSlave:  calcProportions(2),
 			MasterMaster: calcProportions(common.Config.WindowMastersMax),
 			SlaveSlave:   calcProportions(common.Config.WindowSlavesMax),
+			Splits:       calcSplits(common.Config.WindowMastersMax + common.Config.WindowSlavesMax),
+			Columns:      calcProportions(common.Config.WindowMastersMax + common.Config.WindowSlavesMax),
+			Rows:         calcProportions(common.Config.WindowMastersMax + common.Config.WindowSlavesMax),
 		},
 		Masters: &Clients{
 			Maximum: 1,
@@ -129,6 +135,43 @@ func (mg *Manager) MakeMaster(c *Client) {
 	}
 }
 
+func (mg *Manager) MakeSlave(c *Client) {
+	mi := mg.Index(mg.Masters, c)
+	if mi < 0 {
+		return
+	}
+	log.Info("Make window slave [", c.Latest.Class, ", ", mg.Name, "]")
+
+	// Move window to first slave
+	mg.Masters.Stacked = removeClient(mg.Masters.Stacked, mi)
+	mg.Slaves.Stacked = addClient(mg.Slaves.Stacked, c)
+}
+
+func (mg *Manager) PlaceClient(c *Client, master bool, index int) {
+	log.Info("Place window at slot ", index, " [", c.Latest.Class, ", ", mg.Name, "]")
+
+	// Obtain clients without placed window
+	ordered := []*Client{}
+	for _, oc := range mg.Clients(Stacked) {
+		if oc != nil && oc.Window.Id != c.Window.Id {
+			ordered = append(ordered, oc)
+		}
+	}
+
+	// Insert window at slot position
+	i := index
+	if !master {
+		i += mg.Masters.Maximum
+	}
+	i = common.MaxInt(common.MinInt(i, len(ordered)), 0)
+	ordered = append(ordered[:i], append([]*Client{c}, ordered[i:]...)...)
+
+	// Fill up master area then slave area
+	n := common.MinInt(len(ordered), mg.Masters.Maximum)
+	mg.Masters.Stacked = ordered[:n:n]
+	mg.Slaves.Stacked = ordered[n:]
+}
+
 func (mg *Manager) SwapClient(c1 *Client, c2 *Client) {
 	log.Info("Swap clients [", c1.Latest.Class, "-", c2.Latest.Class, ", ", mg.Name, "]")
 
@@ -284,6 +327,12 @@ func (mg *Manager) DecreaseProportion() {
 	mg.SetProportions(mg.Proportions.MasterSlave[2], proportion, 0, 1)
 }
 
+func (mg *Manager) SetProportion(p float64) {
+
+	// Set root proportion
+	mg.SetProportions(mg.Proportions.MasterSlave[2], p, 0, 1)
+}
+
 func (mg *Manager) SetProportions(ps []float64, pi float64, i int, j int) bool {
 
 	// Ignore changes on border sides
@@ -391,3 +440,11 @@ func calcProportions(n int) map[int][]float64 {
 	}
 	return p
 }
+
+func calcSplits(n int) map[int][]float64 {
+	p := map[int][]float64{}
+	for i := 0; i < n; i++ {
+		p[i] = []float64{0.5, 0.5}
+	}
+	return p
+}
@@ -442,6 +442,27 @@ func ScreenGet(p common.Point) uint {
 	return 0
 }
 
+func ScreenInDirection(i uint, direction string) (uint, bool) {
+	if int(i) >= len(Workplace.Displays.Screens) {
+		return 0, false
+	}
+	from := Workplace.Displays.Screens[i].Geometry
+
+	// Obtain nearest screen in direction
+	screen, found, best := uint(0), false, 0
+	for j, s := range Workplace.Displays.Screens {
+		distance, ok := common.IsInDirection(from, s.Geometry, direction)
+		if !ok || uint(j) == i {
+			continue
+		}
+		if !found || distance < best {
+			screen, found, best = uint(j), true, distance
+		}
+	}
+
+	return screen, found
+}
+
 func ScreenGeometry(i uint) *common.Geometry {
 	if int(i) >= len(Workplace.Displays.Screens) {
 		return &common.Geometry{}
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"github.com/leukipp/cortile/v2/common"
+)
+
+type Snap struct {
+	Name     string          // Snap slot name
+	Screen   uint            // Screen index the slot is located
+	Geometry common.Geometry // Geometry of the snap slot
+}
+
+func CreateSnap(name string, screen uint, x int, y int, w int, h int) *Snap {
+	return &Snap{
+		Name:   name,
+		Screen: screen,
+		Geometry: common.Geometry{
+			X:      x,
+			Y:      y,
+			Width:  w,
+			Height: h,
+		},
+	}
+}
+
+func SnapAt(p common.Point) *Snap {
+	screen := ScreenGet(p)
+
+	// Screen and desktop dimensions
+	sx, sy, sw, _ := ScreenGeometry(screen).Pieces()
+	dx, dy, dw, dh := DesktopGeometry(screen).Pieces()
+	size := common.Config.EdgeCornerSize
+
+	// Check if pointer is inside corner rectangle
+	name := ""
+	for _, hc := range Workplace.Displays.Corners {
+		if hc.Screen != screen || !common.IsInList(hc.Name, []string{"top_left", "top_right", "bottom_right", "bottom_left"}) {
+			continue
+		}
+		if common.IsInsideRect(p, hc.Geometry) {
+			name = hc.Name
+		}
+	}
+
+	// Check if pointer is on screen edge
+	if len(name) == 0 {
+		if p.X <= sx+size {
+			name = "left"
+		} else if p.X >= sx+sw-size {
+			name = "right"
+		} else if p.Y <= sy+size {
+			name = "top"
+		}
+	}
+
+	// Define slots and positions
+	switch name {
+	case "top_left":
+		return CreateSnap(name, screen, dx, dy, dw/2, dh/2)
+	case "top_right":
+		return CreateSnap(name, screen, dx+dw/2, dy, dw-dw/2, dh/2)
+	case "bottom_right":
+		return CreateSnap(name, screen, dx+dw/2, dy+dh/2, dw-dw/2, dh-dh/2)
+	case "bottom_left":
+		return CreateSnap(name, screen, dx, dy+dh/2, dw/2, dh-dh/2)
+	case "left":
+		return CreateSnap(name, screen, dx, dy, dw/2, dh)
+	case "right":
+		return CreateSnap(name, screen, dx+dw/2, dy, dw-dw/2, dh)
+	case "top":
+		return CreateSnap(name, screen, dx, dy, dw, dh)
+	}
+
+	return nil
+}
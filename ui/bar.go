@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/jezek/xgbutil"
+	"github.com/jezek/xgbutil/ewmh"
+	"github.com/jezek/xgbutil/icccm"
+	"github.com/jezek/xgbutil/motif"
+	"github.com/jezek/xgbutil/xevent"
+	"github.com/jezek/xgbutil/xgraphics"
+	"github.com/jezek/xgbutil/xwindow"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	bars map[uint]*bar = make(map[uint]*bar) // Layout bar windows per screen
+)
+
+type bar struct {
+	Window   *xwindow.Window // Layout bar window
+	Geometry common.Geometry // Layout bar geometry
+}
+
+func ShowBar(tr *desktop.Tracker) {
+	screens := store.Workplace.Displays.Screens
+
+	for i, screen := range screens {
+		ws := tr.WorkspaceAt(store.Workplace.CurrentDesktop, uint(i))
+
+		// Hide layout bar if disabled
+		if common.Config.TilingBar <= 0 || ws == nil {
+			hideBar(uint(i))
+			continue
+		}
+
+		// Draw layout bar on top of screen
+		x, y, w, _ := screen.Geometry.Pieces()
+		drawBar(tr, ws, common.Geometry{X: x, Y: y, Width: w, Height: common.Config.TilingBar})
+	}
+
+	// Hide layout bars of removed screens
+	for screen := range bars {
+		if int(screen) >= len(screens) {
+			hideBar(screen)
+		}
+	}
+}
+
+func drawBar(tr *desktop.Tracker, ws *desktop.Workspace, geom common.Geometry) {
+	screen := ws.Location.Screen
+	br, ok := bars[screen]
+	if ok && br.Geometry != geom {
+		hideBar(screen)
+		ok = false
+	}
+
+	// Create layout bar window
+	if !ok {
+		br = createBar(tr, screen, geom)
+		if br == nil {
+			return
+		}
+		bars[screen] = br
+	}
+
+	font, err := loadFont()
+	if err != nil {
+		log.Error("Parsing font failed: ", err)
+		return
+	}
+
+	// Obtain workspace infos
+	mg := ws.ActiveLayout().GetManager()
+	state := "enabled"
+	if ws.TilingDisabled() {
+		state = "disabled"
+	}
+	info := fmt.Sprintf("%d  %s  %s  %d/%d", ws.Location.Desktop+1, ws.ActiveLayout().GetName(), state, mg.Masters.Maximum, mg.Slaves.Maximum)
+
+	// Obtain active window title on this screen
+	title := ""
+	if c := tr.ActiveClient(); c != nil && tr.ClientWorkspace(c) == ws {
+		title = c.Latest.Name
+	}
+
+	// Create an empty canvas image
+	bg := bgra("gui_background")
+	cv := xgraphics.New(store.X, image.Rect(0, 0, geom.Width, geom.Height))
+	cv.For(func(x int, y int) xgraphics.BGRA { return bg })
+
+	// Draw workspace infos onto canvas
+	y := geom.Height/2 - tabFontSize*2/3
+	iw, _ := xgraphics.Extents(font, float64(tabFontSize), info)
+	cv.Text(fontMargin, y, bgra("gui_text"), float64(tabFontSize), font, info)
+
+	// Shorten title to remaining width
+	x0 := fontMargin + iw + 4*fontMargin
+	title = shortenText(title, tabFontSize, geom.Width-x0-fontMargin)
+
+	// Draw title onto canvas
+	cv.Text(x0, y, bgra("gui_text"), float64(tabFontSize), font, title)
+
+	// Paint the image onto the window
+	cv.XSurfaceSet(br.Window.Id)
+	cv.XDraw()
+	cv.XPaint(br.Window.Id)
+	cv.Destroy()
+}
+
+func createBar(tr *desktop.Tracker, screen uint, geom common.Geometry) *bar {
+	win, err := xwindow.Generate(store.X)
+	if err != nil {
+		log.Error("Layout bar generation failed: ", err)
+		return nil
+	}
+
+	// Create the layout bar window
+	win.Create(store.X.RootWin(), geom.X, geom.Y, geom.Width, geom.Height, 0)
+
+	// Set class and name
+	icccm.WmClassSet(win.X, win.Id, &icccm.WmClass{
+		Instance: common.Build.Name,
+		Class:    common.Build.Name,
+	})
+	icccm.WmNameSet(win.X, win.Id, common.Build.Name)
+
+	// Set type, states and desktop
+	ewmh.WmWindowTypeSet(win.X, win.Id, []string{
+		"_NET_WM_WINDOW_TYPE_DOCK",
+	})
+	ewmh.WmStateSet(win.X, win.Id, []string{
+		"_NET_WM_STATE_SKIP_TASKBAR",
+		"_NET_WM_STATE_SKIP_PAGER",
+		"_NET_WM_STATE_STICKY",
+	})
+	ewmh.WmDesktopSet(win.X, win.Id, ^uint(0))
+
+	// Reserve space on top of screen
+	ewmh.WmStrutPartialSet(win.X, win.Id, &ewmh.WmStrutPartial{
+		Top:       uint(geom.Y + geom.Height),
+		TopStartX: uint(geom.X),
+		TopEndX:   uint(geom.X + geom.Width - 1),
+	})
+
+	// Set hints for size, decorations and focus
+	icccm.WmNormalHintsSet(win.X, win.Id, &icccm.NormalHints{
+		Flags:     icccm.SizeHintPPosition | icccm.SizeHintPMinSize | icccm.SizeHintPMaxSize,
+		X:         geom.X,
+		Y:         geom.Y,
+		MinWidth:  uint(geom.Width),
+		MinHeight: uint(geom.Height),
+		MaxWidth:  uint(geom.Width),
+		MaxHeight: uint(geom.Height),
+	})
+	icccm.WmHintsSet(win.X, win.Id, &icccm.Hints{
+		Flags: icccm.HintInput,
+		Input: 0,
+	})
+	motif.WmHintsSet(win.X, win.Id, &motif.Hints{
+		Flags:      motif.HintFunctions | motif.HintDecorations,
+		Function:   motif.FunctionNone,
+		Decoration: motif.DecorationNone,
+	})
+
+	// Cycle layouts on bar click
+	win.Listen(xproto.EventMaskButtonPress)
+	xevent.ButtonPressFun(func(X *xgbutil.XUtil, ev xevent.ButtonPressEvent) {
+		switch ev.Detail {
+		case xproto.ButtonIndex1:
+			tr.Channels.Action <- "cycle_next"
+		case xproto.ButtonIndex3:
+			tr.Channels.Action <- "cycle_previous"
+		}
+	}).Connect(store.X, win.Id)
+
+	win.Map()
+
+	return &bar{Window: win, Geometry: geom}
+}
+
+func hideBar(screen uint) {
+	br, ok := bars[screen]
+	if !ok {
+		return
+	}
+
+	// Destroy layout bar window
+	xevent.Detach(store.X, br.Window.Id)
+	br.Window.Destroy()
+	delete(bars, screen)
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"image"
+	"strings"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/jezek/xgbutil/xgraphics"
+	"github.com/jezek/xgbutil/xwindow"
+
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	labels []*xwindow.Window // Hint label windows
+)
+
+func ShowHints(hints map[string]*store.Client) {
+	HideHints()
+
+	size := 2 * guiFontSize()
+	dim := 2 * size
+
+	for key, c := range hints {
+		win, err := xwindow.Generate(store.X)
+		if err != nil {
+			log.Error("Hint generation failed: ", err)
+			continue
+		}
+
+		// Create an empty canvas image
+		bg := bgra("gui_background")
+		cv := xgraphics.New(store.X, image.Rect(0, 0, dim, dim))
+		cv.For(func(x int, y int) xgraphics.BGRA { return bg })
+
+		// Draw hint label
+		drawText(cv, strings.ToUpper(key), bgra("gui_text"), dim/2, (dim+size)/2, size)
+
+		// Create the unmanaged label window
+		cx, cy, cw, ch := c.OuterGeometry()
+		win.Create(store.X.RootWin(), cx+cw/2-dim/2, cy+ch/2-dim/2, dim, dim, xproto.CwOverrideRedirect, 1)
+
+		// Paint the image and map the window
+		cv.XSurfaceSet(win.Id)
+		cv.XDraw()
+		cv.XPaint(win.Id)
+		win.Map()
+
+		labels = append(labels, win)
+	}
+}
+
+func HideHints() {
+	for _, win := range labels {
+		win.Destroy()
+	}
+	labels = nil
+}
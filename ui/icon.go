@@ -68,10 +68,33 @@ func UpdateIcon(ws *desktop.Workspace) {
 		draw.Draw(icon, image.Rect(x0, y0+(y1-y0)/5+layoutMargin/2, x1, y1), &col, image.Point{}, draw.Src)
 	case "fullscreen":
 		draw.Draw(icon, image.Rect(x0, y0, x1, y1), &col, image.Point{}, draw.Src)
+	case "vertical-center":
+		draw.Draw(icon, image.Rect(x0, y0, x0+(x1-x0)/4-layoutMargin, y1), &col, image.Point{}, draw.Src)
+		draw.Draw(icon, image.Rect(x0+(x1-x0)/4+layoutMargin, y0, x0+3*(x1-x0)/4-layoutMargin, y1), &col, image.Point{}, draw.Src)
+		draw.Draw(icon, image.Rect(x0+3*(x1-x0)/4+layoutMargin, y0, x1, y0+(y1-y0)/2-layoutMargin), &col, image.Point{}, draw.Src)
+		draw.Draw(icon, image.Rect(x0+3*(x1-x0)/4+layoutMargin, y0+(y1-y0)/2+layoutMargin, x1, y1), &col, image.Point{}, draw.Src)
+	case "grid":
+		draw.Draw(icon, image.Rect(x0, y0, x0+(x1-x0)/2-layoutMargin, y0+(y1-y0)/2-layoutMargin), &col, image.Point{}, draw.Src)
+		draw.Draw(icon, image.Rect(x0+(x1-x0)/2+layoutMargin, y0, x1, y0+(y1-y0)/2-layoutMargin), &col, image.Point{}, draw.Src)
+		draw.Draw(icon, image.Rect(x0, y0+(y1-y0)/2+layoutMargin, x0+(x1-x0)/2-layoutMargin, y1), &col, image.Point{}, draw.Src)
+		draw.Draw(icon, image.Rect(x0+(x1-x0)/2+layoutMargin, y0+(y1-y0)/2+layoutMargin, x1, y1), &col, image.Point{}, draw.Src)
+	case "tabbed":
+		draw.Draw(icon, image.Rect(x0, y0, x0+(x1-x0)/3-layoutMargin/2, y0+(y1-y0)/5-layoutMargin/2), &col, image.Point{}, draw.Src)
+		draw.Draw(icon, image.Rect(x0+(x1-x0)/3+layoutMargin/2, y0, x0+2*(x1-x0)/3-layoutMargin/2, y0+(y1-y0)/5-layoutMargin/2), &col, image.Point{}, draw.Src)
+		draw.Draw(icon, image.Rect(x0+2*(x1-x0)/3+layoutMargin/2, y0, x1, y0+(y1-y0)/5-layoutMargin/2), &col, image.Point{}, draw.Src)
+		draw.Draw(icon, image.Rect(x0, y0+(y1-y0)/5+layoutMargin/2, x1, y1), &col, image.Point{}, draw.Src)
+	case "dwindle":
+		draw.Draw(icon, image.Rect(x0, y0, x0+(x1-x0)/2-layoutMargin, y1), &col, image.Point{}, draw.Src)
+		draw.Draw(icon, image.Rect(x0+(x1-x0)/2+layoutMargin, y0, x1, y0+(y1-y0)/2-layoutMargin), &col, image.Point{}, draw.Src)
+		draw.Draw(icon, image.Rect(x0+(x1-x0)/2+layoutMargin, y0+(y1-y0)/2+layoutMargin, x0+3*(x1-x0)/4-layoutMargin, y1), &col, image.Point{}, draw.Src)
+		draw.Draw(icon, image.Rect(x0+3*(x1-x0)/4+layoutMargin, y0+(y1-y0)/2+layoutMargin, x1, y1), &col, image.Point{}, draw.Src)
 	case "disabled":
 		draw.Draw(icon, image.Rect(x0, y0, x0+2*layoutMargin, y1-2*layoutMargin), &col, image.Point{}, draw.Src)
 		draw.Draw(icon, image.Rect(x0, y0, x1-2*layoutMargin, y0+2*layoutMargin), &col, image.Point{}, draw.Src)
 		draw.Draw(icon, image.Rect(x0+2*layoutMargin+20, y0+2*layoutMargin+20, x1, y1), &col, image.Point{}, draw.Src)
+	default:
+		draw.Draw(icon, image.Rect(x0, y0, x0+(x1-x0)/2-layoutMargin, y1), &col, image.Point{}, draw.Src)
+		draw.Draw(icon, image.Rect(x0+(x1-x0)/2+layoutMargin, y0, x1, y1), &col, image.Point{}, draw.Src)
 	}
 
 	// Draw hint rectangle
@@ -1,10 +1,15 @@
 package ui
 
 import (
+	"fmt"
 	"image"
 	"math"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
+	"image/color"
 	"image/draw"
 
 	"golang.org/x/image/font/gofont/goregular"
@@ -16,6 +21,7 @@ import (
 	"github.com/jezek/xgbutil/motif"
 	"github.com/jezek/xgbutil/xevent"
 	"github.com/jezek/xgbutil/xgraphics"
+	"github.com/jezek/xgbutil/xprop"
 	"github.com/jezek/xgbutil/xwindow"
 
 	"github.com/leukipp/cortile/v2/common"
@@ -29,12 +35,54 @@ var (
 	fontSize   int = 16 // Size of text font
 	fontMargin int = 4  // Margin of text font
 	rectMargin int = 4  // Margin of layout rectangles
+	guiMargin  int = 16 // Margin of anchored overlay
 )
 
 var (
-	gui map[uint]*xwindow.Window = make(map[uint]*xwindow.Window) // Overlay window
+	gui       map[uint]*xwindow.Window  = make(map[uint]*xwindow.Window)  // Overlay window
+	mode      string                                                      // Active key mode name
+	fonts     map[string]*truetype.Font = make(map[string]*truetype.Font) // Parsed text fonts
+	fontMutex sync.Mutex                                                  // Parsed text fonts lock
 )
 
+type roundedMask struct {
+	Rect   image.Rectangle // Rectangle of mask
+	Radius int             // Radius of rounded corners
+}
+
+func (m *roundedMask) ColorModel() color.Model {
+	return color.AlphaModel
+}
+
+func (m *roundedMask) Bounds() image.Rectangle {
+	return m.Rect
+}
+
+func (m *roundedMask) At(x, y int) color.Color {
+	r := common.MinInt(m.Radius, common.MinInt(m.Rect.Dx(), m.Rect.Dy())/2)
+
+	// Obtain nearest corner center
+	cx, cy := x, y
+	if x < m.Rect.Min.X+r {
+		cx = m.Rect.Min.X + r
+	} else if x >= m.Rect.Max.X-r {
+		cx = m.Rect.Max.X - r - 1
+	}
+	if y < m.Rect.Min.Y+r {
+		cy = m.Rect.Min.Y + r
+	} else if y >= m.Rect.Max.Y-r {
+		cy = m.Rect.Max.Y - r - 1
+	}
+
+	// Check if point is outside corner radius
+	dx, dy := x-cx, y-cy
+	if dx*dx+dy*dy > r*r {
+		return color.Alpha{0}
+	}
+
+	return color.Alpha{255}
+}
+
 func ShowLayout(ws *desktop.Workspace) {
 	location := store.Location{Desktop: store.Workplace.CurrentDesktop}
 	if ws == nil || ws.Location.Desktop != location.Desktop || common.Config.TilingGui <= 0 {
@@ -50,26 +98,44 @@ func ShowLayout(ws *desktop.Workspace) {
 			name = "disabled"
 		}
 
+		// Keep overlay open while key mode is active
+		duration := time.Duration(common.Config.TilingGui)
+		if len(mode) > 0 {
+			duration = 0
+		}
+
 		// Calculate scaled desktop dimensions
 		dim := dimensions(ws)
 		_, _, w, h := scale(dim.X, dim.Y, dim.Width, dim.Height)
 
 		// Create an empty canvas image
+		size := guiFontSize()
 		bg := bgra("gui_background")
-		cv := xgraphics.New(store.X, image.Rect(0, 0, w+rectMargin, h+fontSize+2*fontMargin+2*rectMargin))
+		cv := xgraphics.New(store.X, image.Rect(0, 0, w+rectMargin, h+size+2*fontMargin+2*rectMargin))
 		cv.For(func(x int, y int) xgraphics.BGRA { return bg })
 
 		// Draw client rectangles
 		drawClients(cv, ws, name)
 
-		// Draw layout name
-		drawText(cv, name, bgra("gui_text"), cv.Rect.Dx()/2, cv.Rect.Dy()-2*fontMargin-rectMargin, fontSize)
+		// Draw layout and mode name
+		text := name
+		if len(mode) > 0 {
+			text = fmt.Sprintf("%s [%s]", name, mode)
+		}
+		drawText(cv, text, bgra("gui_text"), cv.Rect.Dx()/2, cv.Rect.Dy()-2*fontMargin-rectMargin, size)
 
 		// Show the canvas graphics
-		showGraphics(cv, ws, time.Duration(common.Config.TilingGui))
+		showGraphics(cv, ws, duration)
 	})
 }
 
+func ShowMode(ws *desktop.Workspace, name string) {
+	mode = name
+
+	// Show or hide mode name
+	ShowLayout(ws)
+}
+
 func drawClients(cv *xgraphics.Image, ws *desktop.Workspace, layout string) {
 	al := ws.ActiveLayout()
 	mg := al.GetManager()
@@ -84,7 +150,7 @@ func drawClients(cv *xgraphics.Image, ws *desktop.Workspace, layout string) {
 
 		// Draw client rectangle onto canvas
 		color := bgra("gui_client_slave")
-		drawImage(cv, &image.Uniform{color}, color, x+rectMargin, y+rectMargin, x+w, y+h)
+		drawRect(cv, color, x+rectMargin, y+rectMargin, x+w, y+h)
 
 		return
 	}
@@ -111,19 +177,46 @@ func drawClients(cv *xgraphics.Image, ws *desktop.Workspace, layout string) {
 
 		// Obtain rectangle color
 		color := bgra("gui_client_slave")
-		if mg.IsMaster(c) || common.IsInList(layout, []string{"maximized", "fullscreen"}) {
+		if mg.IsMaster(c) || common.IsInList(layout, []string{"maximized", "fullscreen", "grid", "tabbed"}) {
 			color = bgra("gui_client_master")
 		}
 
 		// Draw client rectangle onto canvas
-		drawImage(cv, &image.Uniform{color}, color, x+rectMargin, y+rectMargin, x+w, y+h)
+		drawRect(cv, color, x+rectMargin, y+rectMargin, x+w, y+h)
 
 		// Draw client icon onto canvas
-		ico, err := xgraphics.FindIcon(store.X, c.Window.Id, iconSize, iconSize)
-		if err == nil {
-			drawImage(cv, ico, color, x+rectMargin/2+w/2-iconSize/2, y+rectMargin/2+h/2-iconSize/2, x+w, y+h)
+		if guiContent("icon") {
+			ico, err := xgraphics.FindIcon(store.X, c.Window.Id, iconSize, iconSize)
+			if err == nil {
+				drawImage(cv, ico, color, x+rectMargin/2+w/2-iconSize/2, y+rectMargin/2+h/2-iconSize/2, x+w, y+h)
+			}
+		}
+
+		// Draw client title onto canvas
+		if guiContent("title") {
+			size := guiFontSize() * 3 / 4
+			title := shortenText(c.Latest.Name, size, w-2*fontMargin-rectMargin)
+			drawText(cv, title, bgra("gui_text"), x+rectMargin/2+w/2, y+h-fontMargin, size)
 		}
 	}
+
+	// Draw floating client frames
+	for _, c := range ws.Floating {
+		if c.Latest.Location.Desktop != ws.Location.Desktop {
+			continue
+		}
+
+		// Calculate scaled client dimensions
+		cx, cy, cw, ch := c.OuterGeometry()
+		x, y, w, h := scale(cx-dim.X, cy-dim.Y, cw, ch)
+
+		// Draw client frame onto canvas
+		color := bgra("gui_client_slave")
+		drawImage(cv, &image.Uniform{color}, color, x+rectMargin, y+rectMargin, x+w, y+rectMargin+rectMargin/2)
+		drawImage(cv, &image.Uniform{color}, color, x+rectMargin, y+h-rectMargin/2, x+w, y+h)
+		drawImage(cv, &image.Uniform{color}, color, x+rectMargin, y+rectMargin, x+rectMargin+rectMargin/2, y+h)
+		drawImage(cv, &image.Uniform{color}, color, x+w-rectMargin/2, y+rectMargin, x+w, y+h)
+	}
 }
 
 func drawImage(cv *xgraphics.Image, img image.Image, color xgraphics.BGRA, x0 int, y0 int, x1 int, y1 int) {
@@ -135,8 +228,20 @@ func drawImage(cv *xgraphics.Image, img image.Image, color xgraphics.BGRA, x0 in
 	xgraphics.BlendBgColor(cv, color)
 }
 
+func drawRect(cv *xgraphics.Image, color xgraphics.BGRA, x0 int, y0 int, x1 int, y1 int) {
+	radius := common.Config.Gui.Radius
+	if radius <= 0 {
+		drawImage(cv, &image.Uniform{color}, color, x0, y0, x1, y1)
+		return
+	}
+
+	// Draw rectangle with rounded corners
+	rect := image.Rect(x0, y0, x1, y1)
+	draw.DrawMask(cv, rect, &image.Uniform{color}, image.Point{}, &roundedMask{rect, radius}, rect.Min, draw.Over)
+}
+
 func drawText(cv *xgraphics.Image, txt string, color xgraphics.BGRA, x int, y int, size int) {
-	font, err := truetype.Parse(goregular.TTF)
+	font, err := loadFont()
 	if err != nil {
 		log.Error("Parsing font failed: ", err)
 		return
@@ -165,9 +270,27 @@ func showGraphics(img *xgraphics.Image, ws *desktop.Workspace, duration time.Dur
 	w, h := img.Rect.Dx(), img.Rect.Dy()
 	x, y := dim.X+dim.Width/2-w/2, dim.Y+dim.Height/2-h/2
 
+	// Anchor window within desktop
+	position := common.Config.Gui.Position
+	if strings.HasSuffix(position, "left") {
+		x = dim.X + guiMargin
+	} else if strings.HasSuffix(position, "right") {
+		x = dim.X + dim.Width - w - guiMargin
+	}
+	if strings.HasPrefix(position, "top") {
+		y = dim.Y + guiMargin
+	} else if strings.HasPrefix(position, "bottom") {
+		y = dim.Y + dim.Height - h - guiMargin
+	}
+
 	// Create the graphics window
 	win.Create(img.X.RootWin(), x, y, w, h, 0)
 
+	// Set opacity for compositing managers
+	if opacity := common.Config.Gui.Opacity; opacity > 0.0 && opacity < 1.0 {
+		xprop.ChangeProp32(win.X, win.Id, "_NET_WM_WINDOW_OPACITY", "CARDINAL", uint(opacity*float64(^uint32(0))))
+	}
+
 	// Set class and name
 	icccm.WmClassSet(win.X, win.Id, &icccm.WmClass{
 		Instance: common.Build.Name,
@@ -244,10 +367,76 @@ func dimensions(ws *desktop.Workspace) *common.Geometry {
 }
 
 func scale(x, y, w, h int) (sx, sy, sw, sh int) {
-	s := 10
+	s := common.Config.Gui.Scale
+	if s <= 0.0 {
+		s = 0.1
+	}
 
 	// Rescale dimensions by factor s
-	sx, sy, sw, sh = x/s, y/s, w/s, h/s
+	sx, sy, sw, sh = int(float64(x)*s), int(float64(y)*s), int(float64(w)*s), int(float64(h)*s)
 
 	return
 }
+
+func shortenText(txt string, size int, width int) string {
+	font, err := loadFont()
+	if err != nil {
+		return txt
+	}
+
+	// Shorten text to given width
+	text := []rune(txt)
+	w, _ := xgraphics.Extents(font, float64(size), string(text))
+	for len(text) > 0 && w > width {
+		text = text[:len(text)-1]
+		w, _ = xgraphics.Extents(font, float64(size), string(text)+"…")
+	}
+	if len(text) < len([]rune(txt)) {
+		text = append(text, '…')
+	}
+
+	return string(text)
+}
+
+func loadFont() (*truetype.Font, error) {
+	fontMutex.Lock()
+	defer fontMutex.Unlock()
+
+	path := common.Config.Gui.Font
+	if font, ok := fonts[path]; ok {
+		return font, nil
+	}
+
+	// Read font file or use default font
+	data := goregular.TTF
+	if len(path) > 0 {
+		if file, err := os.ReadFile(path); err == nil {
+			data = file
+		} else {
+			log.Warn("Error reading font ", path, ": ", err)
+		}
+	}
+
+	// Parse font data
+	font, err := truetype.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	fonts[path] = font
+
+	return font, nil
+}
+
+func guiContent(name string) bool {
+	if common.Config.Gui.Content == nil {
+		return name == "icon"
+	}
+	return common.IsInList(name, common.Config.Gui.Content)
+}
+
+func guiFontSize() int {
+	if common.Config.Gui.FontSize <= 0 {
+		return fontSize
+	}
+	return common.Config.Gui.FontSize
+}
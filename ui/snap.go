@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/jezek/xgbutil/xprop"
+	"github.com/jezek/xgbutil/xwindow"
+
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	preview *xwindow.Window // Snap preview window
+)
+
+func ShowSnap(tr *desktop.Tracker) {
+	hideSnap()
+
+	// Obtain snap slot of dragged client
+	target, ok := tr.Handlers.SnapClient.Target.(*store.Snap)
+	if !ok || target == nil {
+		return
+	}
+
+	win, err := xwindow.Generate(store.X)
+	if err != nil {
+		log.Error("Snap preview generation failed: ", err)
+		return
+	}
+
+	// Create the unmanaged preview window
+	color := bgra("gui_snap")
+	pixel := uint32(color.R)<<16 | uint32(color.G)<<8 | uint32(color.B)
+	x, y, w, h := target.Geometry.Pieces()
+	win.Create(store.X.RootWin(), x, y, w, h, xproto.CwBackPixel|xproto.CwOverrideRedirect, pixel, 1)
+
+	// Set translucency for compositing managers
+	opacity := uint(float64(color.A) / 255.0 * float64(^uint32(0)))
+	xprop.ChangeProp32(store.X, win.Id, "_NET_WM_WINDOW_OPACITY", "CARDINAL", opacity)
+
+	// Map the preview window
+	win.Map()
+	preview = win
+}
+
+func hideSnap() {
+	if preview == nil {
+		return
+	}
+
+	// Close previous opened window
+	preview.Destroy()
+	preview = nil
+}
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"image"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/jezek/xgbutil"
+	"github.com/jezek/xgbutil/ewmh"
+	"github.com/jezek/xgbutil/icccm"
+	"github.com/jezek/xgbutil/motif"
+	"github.com/jezek/xgbutil/xevent"
+	"github.com/jezek/xgbutil/xgraphics"
+	"github.com/jezek/xgbutil/xwindow"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/layout"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	tabFontSize int = 12 // Size of tab text font
+)
+
+var (
+	tabbars map[store.Location]*tabbar = make(map[store.Location]*tabbar) // Tab bar windows
+)
+
+type tabbar struct {
+	Window   *xwindow.Window // Tab bar window
+	Geometry common.Geometry // Tab bar geometry
+	Clients  []*store.Client // Tab bar clients
+}
+
+func ShowTabs(tr *desktop.Tracker) {
+	for location, ws := range tr.Workspaces {
+		tl, ok := ws.ActiveLayout().(*layout.TabbedLayout)
+		clients := ws.ActiveLayout().GetManager().Clients(store.Stacked)
+
+		// Hide tab bar on other layouts
+		if !ok || ws.TilingDisabled() || len(clients) == 0 || common.Config.TilingTabs <= 0 {
+			hideTabs(location)
+			continue
+		}
+
+		// Draw tab bar
+		drawTabs(ws, tl.TabBar(), clients)
+	}
+}
+
+func drawTabs(ws *desktop.Workspace, geom common.Geometry, clients []*store.Client) {
+	tb, ok := tabbars[ws.Location]
+	if ok && tb.Geometry != geom {
+		hideTabs(ws.Location)
+		ok = false
+	}
+
+	// Create tab bar window
+	if !ok {
+		tb = createTabs(ws, geom)
+		if tb == nil {
+			return
+		}
+		tabbars[ws.Location] = tb
+	}
+	tb.Clients = clients
+
+	font, err := loadFont()
+	if err != nil {
+		log.Error("Parsing font failed: ", err)
+		return
+	}
+
+	// Create an empty canvas image
+	bg := bgra("gui_background")
+	cv := xgraphics.New(store.X, image.Rect(0, 0, geom.Width, geom.Height))
+	cv.For(func(x int, y int) xgraphics.BGRA { return bg })
+
+	// Draw tab rectangles and titles
+	for i, c := range clients {
+		x0, x1 := i*geom.Width/len(clients), (i+1)*geom.Width/len(clients)
+
+		// Obtain tab color
+		color := bgra("gui_client_slave")
+		if c.Window.Id == store.Windows.Active.Id {
+			color = bgra("gui_client_master")
+		}
+		drawImage(cv, &image.Uniform{color}, color, x0+rectMargin/2, rectMargin/2, x1-rectMargin/2, geom.Height-rectMargin/2)
+
+		// Shorten title to tab width
+		title := shortenText(c.Latest.Name, tabFontSize, x1-x0-2*fontMargin-rectMargin)
+		w, _ := xgraphics.Extents(font, float64(tabFontSize), title)
+
+		// Draw title onto canvas
+		cv.Text(x0+(x1-x0)/2-w/2, geom.Height/2-tabFontSize*2/3, bgra("gui_text"), float64(tabFontSize), font, title)
+	}
+
+	// Paint the image onto the window
+	cv.XSurfaceSet(tb.Window.Id)
+	cv.XDraw()
+	cv.XPaint(tb.Window.Id)
+	cv.Destroy()
+}
+
+func createTabs(ws *desktop.Workspace, geom common.Geometry) *tabbar {
+	win, err := xwindow.Generate(store.X)
+	if err != nil {
+		log.Error("Tab bar generation failed: ", err)
+		return nil
+	}
+
+	// Create the tab bar window
+	win.Create(store.X.RootWin(), geom.X, geom.Y, geom.Width, geom.Height, 0)
+
+	// Set class and name
+	icccm.WmClassSet(win.X, win.Id, &icccm.WmClass{
+		Instance: common.Build.Name,
+		Class:    common.Build.Name,
+	})
+	icccm.WmNameSet(win.X, win.Id, common.Build.Name)
+
+	// Set type, states and desktop
+	ewmh.WmWindowTypeSet(win.X, win.Id, []string{
+		"_NET_WM_WINDOW_TYPE_DOCK",
+	})
+	ewmh.WmStateSet(win.X, win.Id, []string{
+		"_NET_WM_STATE_SKIP_TASKBAR",
+		"_NET_WM_STATE_SKIP_PAGER",
+	})
+	ewmh.WmDesktopSet(win.X, win.Id, ws.Location.Desktop)
+
+	// Set hints for size, decorations and focus
+	icccm.WmNormalHintsSet(win.X, win.Id, &icccm.NormalHints{
+		Flags:     icccm.SizeHintPPosition | icccm.SizeHintPMinSize | icccm.SizeHintPMaxSize,
+		X:         geom.X,
+		Y:         geom.Y,
+		MinWidth:  uint(geom.Width),
+		MinHeight: uint(geom.Height),
+		MaxWidth:  uint(geom.Width),
+		MaxHeight: uint(geom.Height),
+	})
+	icccm.WmHintsSet(win.X, win.Id, &icccm.Hints{
+		Flags: icccm.HintInput,
+		Input: 0,
+	})
+	motif.WmHintsSet(win.X, win.Id, &motif.Hints{
+		Flags:      motif.HintFunctions | motif.HintDecorations,
+		Function:   motif.FunctionNone,
+		Decoration: motif.DecorationNone,
+	})
+
+	// Activate window on tab click
+	tb := &tabbar{Window: win, Geometry: geom}
+	win.Listen(xproto.EventMaskButtonPress)
+	xevent.ButtonPressFun(func(X *xgbutil.XUtil, ev xevent.ButtonPressEvent) {
+		clients := tb.Clients
+		if len(clients) == 0 || ev.Detail != xproto.ButtonIndex1 {
+			return
+		}
+		i := int(ev.EventX) * len(clients) / tb.Geometry.Width
+		if i >= 0 && i < len(clients) {
+			store.ActiveWindowSet(store.X, clients[i].Window)
+		}
+	}).Connect(store.X, win.Id)
+
+	win.Map()
+
+	return tb
+}
+
+func hideTabs(location store.Location) {
+	tb, ok := tabbars[location]
+	if !ok {
+		return
+	}
+
+	// Destroy tab bar window
+	xevent.Detach(store.X, tb.Window.Id)
+	tb.Window.Destroy()
+	delete(tabbars, location)
+}